@@ -0,0 +1,37 @@
+package main
+
+import (
+	"runtime"
+	"sync"
+)
+
+// SolveAll solves the boards in place, distributing them across workers
+// goroutines. workers <= 0 means [runtime.NumCPU]. The result of the ith board's
+// [Board.Solve] is the ith element of the returned slice.
+func SolveAll(boards []*Board, workers int) []bool {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	solved := make([]bool, len(boards))
+	jobs := make(chan int)
+	wg := sync.WaitGroup{}
+
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range jobs {
+				solved[n] = boards[n].Solve()
+			}
+		}()
+	}
+
+	for n := range boards {
+		jobs <- n
+	}
+	close(jobs)
+	wg.Wait()
+
+	return solved
+}
@@ -0,0 +1,51 @@
+package main
+
+import "fmt"
+
+// Builder builds a board from givens, one cell at a time:
+//
+//	b, err := NewBuilder().At(0, 0).Is(8).At(1, 2).Is(3).Build()
+type Builder struct {
+	i, j   int
+	digits [Size][Size]uint
+	err    error
+}
+
+// NewBuilder is a [Builder] without givens, at the cell in the 0th row 0th
+// column.
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// At moves the builder to the cell in the ith row jth column.
+func (bl *Builder) At(i, j int) *Builder {
+	if bl.err == nil && (i < 0 || i >= Size || j < 0 || j >= Size) {
+		bl.err = fmt.Errorf("coordinates row %d column %d out of range", i, j)
+	}
+	bl.i, bl.j = i, j
+	return bl
+}
+
+// Is makes d the given of the current cell.
+func (bl *Builder) Is(d uint) *Builder {
+	switch {
+	case bl.err != nil:
+	case d < 1 || d > Size:
+		bl.err = fmt.Errorf("digit %d at row %d column %d out of range", d, bl.i, bl.j)
+	case bl.digits[bl.i][bl.j] != 0 && bl.digits[bl.i][bl.j] != d:
+		bl.err = fmt.Errorf("cell at row %d column %d is already %d", bl.i, bl.j, bl.digits[bl.i][bl.j])
+	default:
+		bl.digits[bl.i][bl.j] = d
+	}
+	return bl
+}
+
+// Build builds the board from the givens with [NewBoardFromDigits]. It returns
+// the first error of the builder calls, or an error if the givens contradict
+// each other.
+func (bl *Builder) Build() (*Board, error) {
+	if bl.err != nil {
+		return nil, bl.err
+	}
+	return NewBoardFromDigits(bl.digits)
+}
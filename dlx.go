@@ -0,0 +1,138 @@
+package main
+
+// SolveDLX solves the sudoku as an exact cover problem using Knuth's dancing
+// links. Every pencilmark of every cell is a candidate, covering one of each
+// of the cell, row, column and box constraints. If the board is not solvable
+// it returns false and the board is left unchanged.
+func (b *Board) SolveDLX() bool {
+	x := newDLX(b)
+	if !x.search() {
+		return false
+	}
+	for _, r := range x.solution {
+		k, d := r/Size, uint(r%Size)+1
+		b.cells[k].Clear().Set(d)
+	}
+	return true
+}
+
+// dlx is the dancing links matrix. Node 0 is the root, nodes 1 to the number
+// of constraints are the column headers, the rest are the candidate nodes.
+type dlx struct {
+	left, right, up, down []int
+	col                   []int // column header of node
+	row                   []int // candidate of node
+	size                  []int // number of nodes in column
+	solution              []int // candidates chosen so far
+}
+
+// newDLX builds the exact cover matrix for the pencilmarks of b. A candidate r
+// is digit r%Size+1 in cell r/Size.
+func newDLX(b *Board) *dlx {
+	const cols = 4 * Size * Size
+
+	x := &dlx{size: make([]int, cols+1)}
+	for n := range cols + 1 {
+		x.left = append(x.left, (n+cols)%(cols+1))
+		x.right = append(x.right, (n+1)%(cols+1))
+		x.up = append(x.up, n)
+		x.down = append(x.down, n)
+		x.col = append(x.col, n)
+		x.row = append(x.row, -1)
+	}
+
+	for k, c := range b.cells {
+		i, j := k/Size, k%Size
+		box := (i/boxHeight)*boxHeight + j/boxWidth
+		for d := range c.Digits() {
+			dd := int(d) - 1
+			x.addRow(k*Size+dd, []int{
+				1 + k,
+				1 + Size*Size + i*Size + dd,
+				1 + 2*Size*Size + j*Size + dd,
+				1 + 3*Size*Size + box*Size + dd,
+			})
+		}
+	}
+	return x
+}
+
+// addRow adds candidate r with nodes in the given columns.
+func (x *dlx) addRow(r int, cols []int) {
+	first := len(x.col)
+	for n, c := range cols {
+		node := first + n
+		x.left = append(x.left, first+(n+len(cols)-1)%len(cols))
+		x.right = append(x.right, first+(n+1)%len(cols))
+		x.up = append(x.up, x.up[c])
+		x.down = append(x.down, c)
+		x.col = append(x.col, c)
+		x.row = append(x.row, r)
+
+		x.down[x.up[c]] = node
+		x.up[c] = node
+		x.size[c]++
+	}
+}
+
+// search is Knuth's algorithm X. It returns whether all columns were covered.
+func (x *dlx) search() bool {
+	if x.right[0] == 0 {
+		return true
+	}
+
+	c := x.right[0]
+	for cc := x.right[c]; cc != 0; cc = x.right[cc] {
+		if x.size[cc] < x.size[c] {
+			c = cc
+		}
+	}
+	if x.size[c] == 0 {
+		return false
+	}
+
+	x.cover(c)
+	for r := x.down[c]; r != c; r = x.down[r] {
+		x.solution = append(x.solution, x.row[r])
+		for n := x.right[r]; n != r; n = x.right[n] {
+			x.cover(x.col[n])
+		}
+
+		if x.search() {
+			return true
+		}
+
+		for n := x.left[r]; n != r; n = x.left[n] {
+			x.uncover(x.col[n])
+		}
+		x.solution = x.solution[:len(x.solution)-1]
+	}
+	x.uncover(c)
+	return false
+}
+
+// cover removes column c and all rows intersecting it from the matrix.
+func (x *dlx) cover(c int) {
+	x.right[x.left[c]] = x.right[c]
+	x.left[x.right[c]] = x.left[c]
+	for r := x.down[c]; r != c; r = x.down[r] {
+		for n := x.right[r]; n != r; n = x.right[n] {
+			x.down[x.up[n]] = x.down[n]
+			x.up[x.down[n]] = x.up[n]
+			x.size[x.col[n]]--
+		}
+	}
+}
+
+// uncover is the inverse of cover.
+func (x *dlx) uncover(c int) {
+	for r := x.up[c]; r != c; r = x.up[r] {
+		for n := x.left[r]; n != r; n = x.left[n] {
+			x.size[x.col[n]]++
+			x.down[x.up[n]] = n
+			x.up[x.down[n]] = n
+		}
+	}
+	x.right[x.left[c]] = c
+	x.left[x.right[c]] = c
+}
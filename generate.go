@@ -0,0 +1,173 @@
+package main
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"slices"
+)
+
+// Generate generates a random solved board. The same seed always generates the
+// same board.
+func Generate(seed int64) *Board {
+	b := EmptyBoard()
+	b.fill(rand.New(rand.NewSource(seed)))
+	return b
+}
+
+// GenerateRandom is [Generate] seeded from [crand.Reader]. Unlike Generate, the
+// boards it generates are unpredictable and not reproducible.
+func GenerateRandom() *Board {
+	seed := [8]byte{}
+	if _, err := crand.Read(seed[:]); err != nil {
+		panic(err)
+	}
+	return Generate(int64(binary.LittleEndian.Uint64(seed[:])))
+}
+
+// GeneratePuzzle generates a random puzzle with a unique solution. Starting
+// from a random solved board it removes clues in random order as long as the
+// solution stays unique. The returned board has only the remaining givens set.
+// The same seed always generates the same puzzle.
+func GeneratePuzzle(seed int64) *Board {
+	return generatePuzzle(seed, func(k int) int { return k })
+}
+
+// GeneratePuzzleSymmetric is [GeneratePuzzle] generating puzzles with 180
+// degree rotational symmetry. Clues are removed in pairs, the cell in the ith
+// row jth column together with the one in the (Size-1-i)th row (Size-1-j)th
+// column. The center cell is removed on its own.
+func GeneratePuzzleSymmetric(seed int64) *Board {
+	return generatePuzzle(seed, func(k int) int { return Size*Size - 1 - k })
+}
+
+// generateAttempts is the number of puzzles [GenerateOfDifficulty] generates
+// before giving up.
+const generateAttempts = 100
+
+// GenerateOfDifficulty generates random puzzles with [GeneratePuzzle] until one
+// of the [Board.Difficulty] target is found. The same seed always generates the
+// same puzzle. It returns an error if target is not a difficulty or no puzzle
+// of the difficulty is found in a limited number of attempts.
+func GenerateOfDifficulty(seed int64, target string) (*Board, error) {
+	if target != "brute" && !slices.ContainsFunc(tiers, func(t tier) bool { return t.name == target }) {
+		return nil, fmt.Errorf("unknown difficulty %q", target)
+	}
+
+	r := rand.New(rand.NewSource(seed))
+	for range generateAttempts {
+		b := GeneratePuzzle(r.Int63())
+		if b.Difficulty() == target {
+			return b, nil
+		}
+	}
+	return nil, fmt.Errorf("no %s puzzle found in %d attempts", target, generateAttempts)
+}
+
+// generatePuzzle is the implementation of [GeneratePuzzle]. The clue at index
+// k is removed together with the clue at index mirror(k).
+func generatePuzzle(seed int64, mirror func(k int) int) *Board {
+	r := rand.New(rand.NewSource(seed))
+
+	b := EmptyBoard()
+	b.fill(r)
+
+	givens := [Size * Size]uint{}
+	for k, c := range b.cells {
+		givens[k] = c.Digit()
+	}
+
+	for _, k := range r.Perm(Size * Size) {
+		m := mirror(k)
+		if givens[k] == 0 {
+			continue
+		}
+
+		d, dm := givens[k], givens[m]
+		givens[k], givens[m] = 0, 0
+		if !fromGivens(&givens).HasUniqueSolution() {
+			givens[k], givens[m] = d, dm
+		}
+	}
+	return fromGivens(&givens)
+}
+
+// IsMinimal determines whether the puzzle has a unique solution that no longer
+// is unique once any of the givens is removed. The board is left unchanged.
+func (b *Board) IsMinimal() bool {
+	givens := b.givenDigits()
+	if !fromGivens(&givens).HasUniqueSolution() {
+		return false
+	}
+
+	for k, d := range givens {
+		if d == 0 {
+			continue
+		}
+		givens[k] = 0
+		unique := fromGivens(&givens).HasUniqueSolution()
+		givens[k] = d
+		if unique {
+			return false
+		}
+	}
+	return true
+}
+
+// Givens returns a board with only the givens of the board set, as they were
+// before any other cell was resolved.
+func (b *Board) Givens() *Board {
+	givens := b.givenDigits()
+	return fromGivens(&givens)
+}
+
+// givenDigits returns the digits of the givens of the board row by row, 0 for
+// the cells that are not givens.
+func (b *Board) givenDigits() [Size * Size]uint {
+	givens := [Size * Size]uint{}
+	for k, g := range b.given {
+		if g {
+			givens[k] = b.cells[k].Digit()
+		}
+	}
+	return givens
+}
+
+// fromGivens builds a board of the non-zero digits of givens, row by row,
+// marked as givens. Unlike [Board.SetGiven] it doesn't drop the givens from
+// their peers, which could leave some of the peers single digit, so the givens
+// are the only single digit cells. [Board.NakedSingles] drops them.
+func fromGivens(givens *[Size * Size]uint) *Board {
+	b := EmptyBoard()
+	for k, d := range givens {
+		if d != 0 {
+			b.cells[k].Clear().Set(d)
+			b.given[k] = true
+		}
+	}
+	return b
+}
+
+// fill is [Board.Solve] without the logical techniques, trying the digits of
+// the guessed cells in random order.
+func (b *Board) fill(r *rand.Rand) bool {
+	i, j, ok := b.Lowest()
+	if !ok {
+		return b.Solved()
+	}
+
+	ds := slices.Collect(b.At(i, j).Digits())
+	r.Shuffle(len(ds), func(x, y int) { ds[x], ds[y] = ds[y], ds[x] })
+
+	for _, d := range ds {
+		cpy := b.Clone()
+
+		if b.set(i, j, d, nil) && b.fill(r) {
+			return true
+		}
+
+		*b = *cpy
+	}
+	return false
+}
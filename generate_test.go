@@ -0,0 +1,14 @@
+//go:build !size4 && !size16
+
+package main
+
+import "testing"
+
+func TestGeneratePuzzleOnlyGivens(t *testing.T) {
+	for seed := range int64(20) {
+		b := GeneratePuzzle(seed)
+		if b.Filled() != b.ClueCount() {
+			t.Errorf("GeneratePuzzle(%d) has %d single digit cells and %d givens", seed, b.Filled(), b.ClueCount())
+		}
+	}
+}
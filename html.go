@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// HTML writes the board to w as an HTML table of class "sudoku". Cells on the
+// top and left edges of a box have the classes "box-top" and "box-left", so the
+// box boundaries can be styled. Single digit cells have the class "solved" and
+// contain their digit, other cells have the class "candidates" and contain the
+// list of their pencilmarks.
+func (b *Board) HTML(w io.Writer) error {
+	s := strings.Builder{}
+	s.WriteString("<table class=\"sudoku\">\n")
+	for i := range Size {
+		s.WriteString("<tr>\n")
+		for j, c := range b.Row(i) {
+			classes := []string{}
+			if i%boxHeight == 0 {
+				classes = append(classes, "box-top")
+			}
+			if j%boxWidth == 0 {
+				classes = append(classes, "box-left")
+			}
+
+			if c.Single() {
+				classes = append(classes, "solved")
+				fmt.Fprintf(&s, "<td class=\"%s\">%c</td>\n", strings.Join(classes, " "), digitChar(c.Digit()))
+				continue
+			}
+
+			classes = append(classes, "candidates")
+			fmt.Fprintf(&s, "<td class=\"%s\"><ul>", strings.Join(classes, " "))
+			for d := range c.Digits() {
+				fmt.Fprintf(&s, "<li>%c</li>", digitChar(d))
+			}
+			s.WriteString("</ul></td>\n")
+		}
+		s.WriteString("</tr>\n")
+	}
+	s.WriteString("</table>\n")
+
+	_, err := io.WriteString(w, s.String())
+	return err
+}
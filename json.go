@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// MarshalJSON encodes the board as an array of rows, each an array of digits.
+// Cells that are not single digit are encoded as 0.
+func (b *Board) MarshalJSON() ([]byte, error) {
+	rows := [Size][Size]uint{}
+	for i := range Size {
+		for j, c := range b.Row(i) {
+			if c.Single() {
+				rows[i][j] = c.Digit()
+			}
+		}
+	}
+	return json.Marshal(rows)
+}
+
+// UnmarshalJSON decodes the board from the form produced by
+// [Board.MarshalJSON] using [NewBoardFromDigits]. On error the board is left
+// unchanged.
+func (b *Board) UnmarshalJSON(data []byte) error {
+	rows := [][]uint{}
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return err
+	}
+
+	if len(rows) != Size {
+		return fmt.Errorf("board has %d rows, expected %d", len(rows), Size)
+	}
+
+	digits := [Size][Size]uint{}
+	for i, row := range rows {
+		if len(row) != Size {
+			return fmt.Errorf("row %d has %d cells, expected %d", i, len(row), Size)
+		}
+		copy(digits[i][:], row)
+	}
+
+	n, err := NewBoardFromDigits(digits)
+	if err != nil {
+		return err
+	}
+
+	*b = *n
+	return nil
+}
+
+// MarshalCandidates encodes the pencilmarks of the board as an array of rows,
+// each an array of the pencilmark digits of the cells.
+func (b *Board) MarshalCandidates() ([]byte, error) {
+	rows := [Size][Size][]uint{}
+	for i := range Size {
+		for j, c := range b.Row(i) {
+			rows[i][j] = slices.AppendSeq([]uint{}, c.Digits())
+		}
+	}
+	return json.Marshal(rows)
+}
+
+// UnmarshalCandidates decodes the board from the form produced by
+// [Board.MarshalCandidates], restoring the pencilmarks of all cells as they
+// are. No cell is marked as a given. On error the board is left unchanged.
+func (b *Board) UnmarshalCandidates(data []byte) error {
+	rows := [][][]uint{}
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return err
+	}
+
+	if len(rows) != Size {
+		return fmt.Errorf("board has %d rows, expected %d", len(rows), Size)
+	}
+
+	n := Board{}
+	for i, row := range rows {
+		if len(row) != Size {
+			return fmt.Errorf("row %d has %d cells, expected %d", i, len(row), Size)
+		}
+		for j, ds := range row {
+			for _, d := range ds {
+				if d < 1 || d > Size {
+					return fmt.Errorf("digit %d at row %d column %d out of range", d, i, j)
+				}
+				n.At(i, j).Set(d)
+			}
+		}
+	}
+
+	*b = n
+	return nil
+}
@@ -1,18 +1,29 @@
 package main
 
 import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"flag"
 	"fmt"
+	"hash/fnv"
+	"io"
 	"iter"
 	"math/bits"
+	"os"
+	"slices"
 	"strings"
+	"time"
 )
 
-// Size of the sudoku. This can't really be changed easily because the 3x3 box
-// logic assumes a 9 grid sudoku.
-const Size = 9
+// Size of the sudoku. It is derived from the box dimensions, which are chosen
+// at compile time with the size4 and size16 build tags, the default being the
+// classic 9x9 sudoku.
+const Size = boxWidth * boxHeight
 
-// Cell is a one hot encoding of pencil marks. Lowest 9 bits used to indicate
-// what values a cell can take.
+// Cell is a one hot encoding of pencil marks. Lowest [Size] bits used to
+// indicate what values a cell can take. 16 bits are enough for the 16x16
+// sudoku.
 type Cell uint16
 
 // All is cell that has no information - can take all values.
@@ -38,6 +49,13 @@ func (c *Cell) Drop(i uint) *Cell {
 	return c
 }
 
+// Toggle adds the pencilmark to a cell if it's missing, removes it otherwise. It
+// returns self reference for chaining.
+func (c *Cell) Toggle(i uint) *Cell {
+	*c ^= 1 << (i - 1)
+	return c
+}
+
 // IsSet determines wheter a pencilmark is set in cell.
 func (c *Cell) IsSet(i uint) bool {
 	return (*c)&(1<<(i-1)) != 0
@@ -65,17 +83,37 @@ func (c Cell) Digits() iter.Seq[uint] {
 	}
 }
 
+// Slice is the possible (pencilmarked) digits in a cell in ascending order.
+func (c Cell) Slice() []uint {
+	return slices.Collect(c.Digits())
+}
+
 // Count is the number of possible digits (pencilmarks) in a cell.
 func (c Cell) Count() int {
 	return bits.OnesCount(uint(c))
 }
 
+// Union is the pencilmarks that are in either of the cells.
+func (c Cell) Union(o Cell) Cell {
+	return c | o
+}
+
+// Intersect is the pencilmarks that are in both cells.
+func (c Cell) Intersect(o Cell) Cell {
+	return c & o
+}
+
+// Without is the pencilmarks of c that are not in o.
+func (c Cell) Without(o Cell) Cell {
+	return c &^ o & All()
+}
+
 // String is the string representation of a cell.
 func (c Cell) String() string {
 	b := strings.Builder{}
 	for i := uint(Size); i > 0; i-- {
 		if c.IsSet(i) {
-			b.WriteString(fmt.Sprintf("%d", i))
+			b.WriteByte(digitChar(i))
 		} else {
 			b.WriteString("_")
 		}
@@ -83,21 +121,162 @@ func (c Cell) String() string {
 	return b.String()
 }
 
-// Bpard is a sudoku board.
-type Board [Size * Size]Cell
+// Board is a sudoku board. Besides the cells it keeps track of which cells
+// were set as givens.
+type Board struct {
+	cells [Size * Size]Cell
+	given [Size * Size]bool
+}
 
 // EmptyBoard is a sudoku board where all cells are [All].
 func EmptyBoard() *Board {
 	b := Board{}
 	for i := range Size * Size {
-		b[i] = All()
+		b.cells[i] = All()
 	}
 	return &b
 }
 
+// Reset sets every cell back to [All] and forgets the givens, as in
+// [EmptyBoard].
+func (b *Board) Reset() {
+	*b = *EmptyBoard()
+}
+
+// Clone returns a copy of the board with the same pencilmarks.
+func (b *Board) Clone() *Board {
+	c := *b
+	return &c
+}
+
+// BoardSnapshot is the saved state of a board, taken by [Board.Snapshot].
+type BoardSnapshot struct {
+	board Board
+}
+
+// Snapshot saves the pencilmarks and givens of the board so they can be put
+// back with [Board.Restore].
+func (b *Board) Snapshot() BoardSnapshot {
+	return BoardSnapshot{*b}
+}
+
+// Restore puts back the state of the board saved in s.
+func (b *Board) Restore(s BoardSnapshot) {
+	*b = s.board
+}
+
+// Equal determines whether the two boards have the same pencilmarks in all
+// cells. Two nil boards are equal, a nil board is not equal to a non-nil one.
+func (b *Board) Equal(other *Board) bool {
+	if b == nil || other == nil {
+		return b == other
+	}
+	return b.cells == other.cells
+}
+
+// CellChange is a cell in the Ith row Jth column that has the pencilmarks From
+// in one board and To in another.
+type CellChange struct {
+	I, J     int
+	From, To Cell
+}
+
+// Diff returns the cells that have different pencilmarks in a and b, row by
+// row.
+func Diff(a, b *Board) []CellChange {
+	changes := []CellChange{}
+	for k := range Size * Size {
+		if a.cells[k] != b.cells[k] {
+			changes = append(changes, CellChange{k / Size, k % Size, a.cells[k], b.cells[k]})
+		}
+	}
+	return changes
+}
+
+// Hash is an FNV-1a hash of the pencilmarks of all cells. Boards that are
+// [Board.Equal] have the same hash.
+func (b *Board) Hash() uint64 {
+	buf := make([]byte, 0, 2*Size*Size)
+	for _, c := range b.cells {
+		buf = binary.LittleEndian.AppendUint16(buf, uint16(c))
+	}
+	h := fnv.New64a()
+	h.Write(buf)
+	return h.Sum64()
+}
+
+// NewBoard is an [EmptyBoard] with boxes of boxW columns and boxH rows. The box
+// dimensions are fixed at compile time, it returns an error if they differ
+// from boxW and boxH.
+func NewBoard(boxW, boxH int) (*Board, error) {
+	if boxW != boxWidth || boxH != boxHeight {
+		return nil, fmt.Errorf("box size %dx%d is not supported, built for %dx%d", boxW, boxH, boxWidth, boxHeight)
+	}
+	return EmptyBoard(), nil
+}
+
+// NewBoardFromDigits builds a board from the digits of each row, 0 meaning an
+// empty cell. Non-zero digits are set with [Board.TrySet] on an empty board and
+// marked as givens. It returns an error if a digit is out of range or
+// contradicts the digits before it.
+func NewBoardFromDigits(d [Size][Size]uint) (*Board, error) {
+	b := EmptyBoard()
+	for i, row := range d {
+		for j, d := range row {
+			if d > Size {
+				return nil, fmt.Errorf("digit %d at row %d column %d out of range", d, i, j)
+			}
+			if d == 0 {
+				continue
+			}
+			if err := b.TrySet(i, j, d); err != nil {
+				return nil, err
+			}
+			b.given[i*Size+j] = true
+		}
+	}
+	return b, nil
+}
+
 // At returns a cell pointer to the ith row jth column.
 func (b *Board) At(i, j int) *Cell {
-	return &b[i*Size+j]
+	return &b.cells[i*Size+j]
+}
+
+// AtSafe is [Board.At] with bounds checking. It returns an error if i or j is
+// out of range.
+func (b *Board) AtSafe(i, j int) (*Cell, error) {
+	if i < 0 || i >= Size || j < 0 || j >= Size {
+		return nil, fmt.Errorf("coordinates row %d column %d out of range", i, j)
+	}
+	return b.At(i, j), nil
+}
+
+// Get returns the digit of the cell in the ith row jth column if it is a single
+// digit, otherwise, or if the coordinates are out of range, it returns resolved
+// false.
+func (b *Board) Get(i, j int) (d uint, resolved bool) {
+	c, err := b.AtSafe(i, j)
+	if err != nil || !c.Single() {
+		return 0, false
+	}
+	return c.Digit(), true
+}
+
+// CanPlace determines whether d is a pencilmark of the cell in the ith row jth
+// column and no single digit peer of the cell is d. It returns false if the
+// coordinates or d are out of range.
+func (b *Board) CanPlace(i, j int, d uint) bool {
+	c, err := b.AtSafe(i, j)
+	if err != nil || d < 1 || d > Size || !c.IsSet(d) {
+		return false
+	}
+	for _, p := range b.Peers(i, j) {
+		if p.Single() && p.IsSet(d) {
+			return false
+		}
+	}
+	return true
 }
 
 // Row iteraters the column indices along with the corresponding cell from the ith row.
@@ -122,13 +301,20 @@ func (b *Board) Col(j int) iter.Seq2[int, *Cell] {
 	}
 }
 
-// Box iterates the indices along with the corresponding cell from the 3x3 box
-// that the cell with coordinates i, j fall into. It does not include the cell
+// BoxOrigin returns the coordinates of the top left cell of the box that the
+// cell with coordinates i, j falls into.
+func BoxOrigin(i, j int) (int, int) {
+	return (i / boxHeight) * boxHeight, (j / boxWidth) * boxWidth
+}
+
+// Box iterates the indices along with the corresponding cell from the box that
+// the cell with coordinates i, j fall into. It does not include the cell
 // itself, skipping i and j.
 func (b *Board) Box(i, j int) iter.Seq2[[]int, *Cell] {
+	bx, by := BoxOrigin(i, j)
 	return func(yield func([]int, *Cell) bool) {
-		for x := (i / 3) * 3; x < (i/3)*3+3; x++ {
-			for y := (j / 3) * 3; y < (j/3)*3+3; y++ {
+		for x := bx; x < bx+boxHeight; x++ {
+			for y := by; y < by+boxWidth; y++ {
 				if x == i && y == j {
 					continue
 				}
@@ -140,45 +326,187 @@ func (b *Board) Box(i, j int) iter.Seq2[[]int, *Cell] {
 	}
 }
 
+// BoxAll is [Board.Box] including the cell with coordinates i, j itself.
+func (b *Board) BoxAll(i, j int) iter.Seq2[[]int, *Cell] {
+	bx, by := BoxOrigin(i, j)
+	return func(yield func([]int, *Cell) bool) {
+		for x := bx; x < bx+boxHeight; x++ {
+			for y := by; y < by+boxWidth; y++ {
+				if !yield([]int{x, y}, b.At(x, y)) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// BoxByIndex iterates the coordinates along with the corresponding cell of the
+// nth box, numbering the boxes row by row from 0 for the top left box.
+func (b *Board) BoxByIndex(n int) iter.Seq2[[]int, *Cell] {
+	return b.BoxAll((n/boxHeight)*boxHeight, (n%boxHeight)*boxWidth)
+}
+
+// Peers iterates the coordinates along with the corresponding cell of all cells
+// that share a row, column or box with the cell with coordinates i, j. Each
+// peer is yielded once and the cell itself is skipped.
+func (b *Board) Peers(i, j int) iter.Seq2[[]int, *Cell] {
+	return func(yield func([]int, *Cell) bool) {
+		for jj, c := range b.Row(i) {
+			if jj != j && !yield([]int{i, jj}, c) {
+				return
+			}
+		}
+		for ii, c := range b.Col(j) {
+			if ii != i && !yield([]int{ii, j}, c) {
+				return
+			}
+		}
+		for xy, c := range b.Box(i, j) {
+			if xy[0] != i && xy[1] != j && !yield(xy, c) {
+				return
+			}
+		}
+	}
+}
+
+// Unsolved iterates the coordinates along with the corresponding cell of all
+// cells that are not single digit, row by row.
+func (b *Board) Unsolved() iter.Seq2[[]int, *Cell] {
+	return func(yield func([]int, *Cell) bool) {
+		for i := range Size {
+			for j, c := range b.Row(i) {
+				if !c.Single() && !yield([]int{i, j}, c) {
+					return
+				}
+			}
+		}
+	}
+}
+
 // Set sets (resolves) the cell digit to be d for the ith row jth column. It
 // removes the pencilmarks from affected cells following sudoku rules, then
-// recursively sets any cell that becomes a single digit pencilmark.
+// does the same for any cell that becomes a single digit pencilmark, until no
+// more cells become single.
 func (b *Board) Set(i, j int, d uint) {
-	b.At(i, j).Clear().Set(d)
+	b.set(i, j, d, nil)
+}
 
-	for jj, c := range b.Row(i) {
-		if j != jj && c.IsSet(d) && c.Drop(d).Single() {
-			b.Set(i, jj, c.Digit())
-		}
+// SetGiven is [Board.Set] marking the cell as a given of the puzzle.
+func (b *Board) SetGiven(i, j int, d uint) {
+	b.Set(i, j, d)
+	b.given[i*Size+j] = true
+}
+
+// TrySet is [Board.Set] with checks. It returns an error if the coordinates
+// are out of range, if d is not a pencilmark of the ith row jth column, or if
+// the propagation leaves a cell without pencilmarks, in which case the board is
+// left unchanged.
+func (b *Board) TrySet(i, j int, d uint) error {
+	c, err := b.AtSafe(i, j)
+	if err != nil {
+		return err
+	}
+	if !c.IsSet(d) {
+		return fmt.Errorf("cell at row %d column %d can't take %d", i, j, d)
 	}
-	for ii, c := range b.Col(j) {
-		if i != ii && c.IsSet(d) && c.Drop(d).Single() {
-			b.Set(ii, j, c.Digit())
+
+	t := trail{}
+	if !b.set(i, j, d, &t) {
+		b.undo(&t, 0)
+		return fmt.Errorf("setting %d at row %d column %d leads to a contradiction", d, i, j)
+	}
+	return nil
+}
+
+// set is the implementation of [Board.Set]. Unless t is nil, it records the
+// changed cells on t. It returns false if any cell is left without
+// pencilmarks.
+func (b *Board) set(i, j int, d uint, t *trail) bool {
+	k := i*Size + j
+	t.record(b, k)
+	b.cells[k].Clear().Set(d)
+
+	// queue is the worklist of cells whose digit is to be removed from their
+	// peers. A cell becomes single at most once, so it never overflows.
+	queue := [Size * Size]struct {
+		k int
+		d uint
+	}{{k, d}}
+
+	ok := true
+	for head, tail := 0, 1; head < tail; head++ {
+		k, d := queue[head].k, queue[head].d
+		for _, p := range peers[k] {
+			c := &b.cells[p]
+			if !c.IsSet(d) {
+				continue
+			}
+			t.record(b, p)
+			if c.Drop(d).Single() {
+				queue[tail].k, queue[tail].d = p, c.Digit()
+				tail++
+			} else if *c == 0 {
+				ok = false
+			}
 		}
 	}
-	for xy, c := range b.Box(i, j) {
-		if c.IsSet(d) && c.Drop(d).Single() {
-			b.Set(xy[0], xy[1], c.Digit())
+	return ok
+}
+
+// peers are the indices of the [Board.Peers] of each cell, for the hot path in
+// [Board.set].
+var peers = func() (p [Size * Size][]int) {
+	b := EmptyBoard()
+	for k := range Size * Size {
+		for xy := range b.Peers(k/Size, k%Size) {
+			p[k] = append(p[k], xy[0]*Size+xy[1])
 		}
 	}
+	return p
+}()
+
+// trail is a record of cell changes, so they can be undone.
+type trail []change
+
+// change is a cell index along with the cell value before it was changed.
+type change struct {
+	k int
+	c Cell
+}
+
+// record records the kth cell of b before it's changed. It does nothing on a
+// nil trail.
+func (t *trail) record(b *Board, k int) {
+	if t != nil {
+		*t = append(*t, change{k, b.cells[k]})
+	}
+}
+
+// undo reverts the changes recorded on t after the first n, removing them
+// from t.
+func (b *Board) undo(t *trail, n int) {
+	for len(*t) > n {
+		ch := (*t)[len(*t)-1]
+		b.cells[ch.k] = ch.c
+		*t = (*t)[:len(*t)-1]
+	}
 }
 
 // Lowest is the coordinates of the lowest bitcount (fewest pencilmark) cell
-// that is not a single digit - if any. If none found it returns ok false.
+// that is not a single digit - if any. Ties are broken in row by row order,
+// it returns the first of the cells with the fewest pencilmarks. If none found
+// it returns ok false.
 func (b *Board) Lowest() (i, j int, ok bool) {
-	lowest := Size + 1
-	for ii := range Size {
-		for jj := range Size {
-			c := b.At(ii, jj).Count()
-			if 1 < c && c < lowest {
-				lowest = c
-				i = ii
-				j = jj
-				ok = true
-			}
+	lowest, at := Size+1, -1
+	for k, c := range b.cells {
+		if cnt := c.Count(); 1 < cnt && cnt < lowest {
+			lowest, at = cnt, k
 		}
 	}
-	return i, j, ok
+	if at < 0 {
+		return 0, 0, false
+	}
+	return at / Size, at % Size, true
 }
 
 // Solved decides if the board contains only single digit cells.
@@ -193,72 +521,666 @@ func (b *Board) Solved() bool {
 	return true
 }
 
-// Solve solves the sudoku by guessing the [Lowest] cell recursively. If the
-// board is not solvable it returns false.
+// Filled is the number of single digit cells.
+func (b *Board) Filled() int {
+	cnt := 0
+	for _, c := range b.cells {
+		if c.Single() {
+			cnt++
+		}
+	}
+	return cnt
+}
+
+// ClueCount is the number of givens of the puzzle. If no cell is marked as a
+// given, it's the number of single digit cells.
+func (b *Board) ClueCount() int {
+	cnt := 0
+	for _, g := range b.given {
+		if g {
+			cnt++
+		}
+	}
+	if cnt == 0 {
+		return b.Filled()
+	}
+	return cnt
+}
+
+// IsSolved determines whether the board is a correct solution: all cells are
+// single digit and every row, column and box contains all digits.
+func (b *Board) IsSolved() bool {
+	if !b.Solved() {
+		return false
+	}
+	for unit := range b.Units() {
+		seen := Cell(0)
+		for _, c := range unit {
+			seen = seen.Union(*c)
+		}
+		if seen != All() {
+			return false
+		}
+	}
+	return true
+}
+
+// IsValid determines whether the board doesn't break the rules: every cell has
+// a pencilmark, and no two single digit cells in a row, column or box have the
+// same digit.
+func (b *Board) IsValid() bool {
+	for _, c := range b.cells {
+		if c == 0 {
+			return false
+		}
+	}
+	for unit := range b.Units() {
+		seen := Cell(0)
+		for _, c := range unit {
+			if !c.Single() {
+				continue
+			}
+			if seen&*c != 0 {
+				return false
+			}
+			seen = seen.Union(*c)
+		}
+	}
+	return true
+}
+
+// BoardStatus is the state of a board reported by [Board.Status].
+type BoardStatus int
+
+const (
+	// Empty is a board without single digit cells.
+	Empty BoardStatus = iota
+	// Partial is a valid board with some single digit cells.
+	Partial
+	// Solved is a correctly solved board.
+	Solved
+	// Invalid is a board breaking the rules, see [Board.IsValid].
+	Invalid
+)
+
+// String is the name of the status.
+func (s BoardStatus) String() string {
+	switch s {
+	case Empty:
+		return "empty"
+	case Partial:
+		return "partial"
+	case Solved:
+		return "solved"
+	case Invalid:
+		return "invalid"
+	}
+	return fmt.Sprintf("BoardStatus(%d)", int(s))
+}
+
+// Status determines the state of the board.
+func (b *Board) Status() BoardStatus {
+	switch {
+	case !b.IsValid():
+		return Invalid
+	case b.Filled() == 0:
+		return Empty
+	case b.IsSolved():
+		return Solved
+	}
+	return Partial
+}
+
+// Conflicts returns the coordinates of the single digit cells that have the
+// same digit as another single digit cell in their row, column or box. Each
+// cell is listed once, row by row.
+func (b *Board) Conflicts() [][2]int {
+	conflict := [Size * Size]bool{}
+	for unit := range b.units() {
+		at := [Size + 1][][]int{}
+		for xy, c := range unit {
+			if c.Single() {
+				at[c.Digit()] = append(at[c.Digit()], xy)
+			}
+		}
+		for _, xys := range at {
+			if len(xys) < 2 {
+				continue
+			}
+			for _, xy := range xys {
+				conflict[xy[0]*Size+xy[1]] = true
+			}
+		}
+	}
+
+	conflicts := [][2]int{}
+	for k, c := range conflict {
+		if c {
+			conflicts = append(conflicts, [2]int{k / Size, k % Size})
+		}
+	}
+	return conflicts
+}
+
+// Solve solves the sudoku by running [Board.Propagate] once and then guessing
+// the [Lowest] cell recursively, applying the naked and hidden singles after
+// each guess. If the board is not solvable it returns false.
 func (b *Board) Solve() bool {
+	solved, _ := b.SolveContext(context.Background())
+	return solved
+}
+
+// SolveContext is [Board.Solve] that can be canceled through ctx. ctx is
+// checked at each guess, and once it is done SolveContext returns ctx.Err(),
+// leaving the board with the deductions made before the first guess.
+func (b *Board) SolveContext(ctx context.Context) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	solved, err := b.PropagateE()
+	if solved || err != nil {
+		return solved, nil
+	}
+
+	return b.search(ctx, &stats{})
+}
+
+// SolveStats is [Board.Solve] also reporting the number of digits guessed in
+// [Lowest] cells and the number of times a guess was undone.
+func (b *Board) SolveStats() (solved bool, guesses int, backtracks int) {
+	if b.Propagate() {
+		return true, 0, 0
+	}
+
+	s := stats{}
+	solved, _ = b.search(context.Background(), &s)
+	return solved, s.guesses, s.backtracks
+}
+
+// SearchCost is the number of guesses [Board.SolveStats] makes, including the
+// ones undone later. The board is left unchanged.
+func (b *Board) SearchCost() int {
+	_, guesses, _ := b.Clone().SolveStats()
+	return guesses
+}
+
+// SolveTimed is [Board.Solve] also reporting the wall clock time it took.
+func (b *Board) SolveTimed() (bool, time.Duration) {
+	start := time.Now()
+	solved := b.Solve()
+	return solved, time.Since(start)
+}
+
+// SolveSteps solves the sudoku like [Board.Solve], yielding a copy of the board
+// after each deduction of the techniques and each guess. If the deductions
+// leave the board breaking the rules, see [Board.IsValid], it doesn't guess.
+// If the last step doesn't leave the board solved, or there are no steps, it
+// finally yields the board as it's left. The board ends up in the state of the
+// last yielded copy.
+func (b *Board) SolveSteps() iter.Seq[*Board] {
+	return func(yield func(*Board) bool) {
+		stepped := false
+		for range b.replay() {
+			stepped = true
+			if !yield(b.Clone()) {
+				return
+			}
+		}
+
+		solved := b.Solved()
+		if !solved && b.IsValid() {
+			var more bool
+			if solved, more = b.steps(yield, &trail{}); !more {
+				return
+			}
+			stepped = true
+		}
+
+		if !solved || !stepped {
+			yield(b.Clone())
+		}
+	}
+}
+
+// steps is the guessing part of [Board.SolveSteps], undoing the changes
+// recorded on t when backtracking. more is false once yield returned false.
+func (b *Board) steps(yield func(*Board) bool, t *trail) (solved, more bool) {
 	i, j, ok := b.Lowest()
 	if !ok {
-		return b.Solved()
+		return b.Solved(), true
+	}
+
+	for d := range b.At(i, j).Digits() {
+		n := len(*t)
+
+		if b.set(i, j, d, t) {
+			if !yield(b.Clone()) {
+				return false, false
+			}
+			if solved, more := b.steps(yield, t); solved || !more {
+				return solved, more
+			}
+		}
+
+		b.undo(t, n)
+	}
+	return false, true
+}
+
+// stats are the counters of [Board.SolveStats].
+type stats struct {
+	guesses, backtracks int
+}
+
+// search is the guessing part of [Board.SolveContext]. After each guess it
+// applies the [singles] with [Board.propagateE], so that the guesses they
+// rule out are dropped before guessing further. The techniques don't record
+// their changes on a trail, so instead of undoing the changes it restores a
+// snapshot of the board when backtracking. It counts the guesses and
+// backtracks on s.
+func (b *Board) search(ctx context.Context, s *stats) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	i, j, ok := b.Lowest()
+	if !ok {
+		return b.Solved(), nil
 	}
 
+	snapshot := b.Snapshot()
 	for d := range b.At(i, j).Digits() {
-		cpy := Board{}
-		copy(cpy[:], b[:])
+		s.guesses++
 
-		b.Set(i, j, d)
+		if b.set(i, j, d, nil) {
+			if _, err := b.propagateE(singles); err == nil {
+				solved, err := b.search(ctx, s)
+				if err != nil {
+					b.Restore(snapshot)
+					return false, err
+				}
+				if solved {
+					return true, nil
+				}
+			}
+		}
+
+		b.Restore(snapshot)
+		s.backtracks++
+	}
+	return false, nil
+}
+
+// MostConstrainedDigit is the digit that is a pencilmark of the fewest cells
+// that are not single digit. If all cells are single digit it returns ok false.
+func (b *Board) MostConstrainedDigit() (d uint, ok bool) {
+	counts := [Size + 1]int{}
+	for _, c := range b.Unsolved() {
+		for d := range c.Digits() {
+			counts[d]++
+		}
+	}
+
+	for dd := uint(1); dd <= Size; dd++ {
+		if counts[dd] > 0 && (!ok || counts[dd] < counts[d]) {
+			d, ok = dd, true
+		}
+	}
+	return d, ok
+}
+
+// SolveByDigit is [Board.Solve] guessing on digits instead of cells. It takes
+// the [Board.MostConstrainedDigit] and tries it in each cell of the row, column
+// or box where it has the fewest places, recursively. If the board is not
+// solvable it returns false.
+func (b *Board) SolveByDigit() bool {
+	solved, err := b.PropagateE()
+	if solved || err != nil {
+		return solved
+	}
+
+	t := trail{}
+	if !b.searchDigit(&t) {
+		b.undo(&t, 0)
+		return false
+	}
+	return true
+}
+
+// searchDigit is the guessing part of [Board.SolveByDigit], undoing the
+// changes recorded on t when backtracking.
+func (b *Board) searchDigit(t *trail) bool {
+	d, ok := b.MostConstrainedDigit()
+	if !ok {
+		return b.Solved()
+	}
 
-		if b.Solve() {
+	for _, xy := range b.places(d) {
+		n := len(*t)
+
+		if b.set(xy[0], xy[1], d, t) && b.searchDigit(t) {
 			return true
 		}
 
-		copy(b[:], cpy[:])
+		b.undo(t, n)
 	}
 	return false
 }
 
-// Print prints the sudoku board. (all pencilmarks for all cells.)
+// places returns the coordinates of the cells that can take d in the row,
+// column or box with the fewest such cells, ignoring the units where d is
+// already resolved.
+func (b *Board) places(d uint) [][]int {
+	var fewest []int
+	cnt := 0
+	for _, unit := range unitIndices {
+		cells := b.unitCells(unit)
+		if slices.ContainsFunc(cells[:], func(c *Cell) bool { return c.Single() && c.IsSet(d) }) {
+			continue
+		}
+		if n := CountDigit(cells[:], d); fewest == nil || n < cnt {
+			fewest, cnt = unit, n
+		}
+	}
+	if fewest == nil {
+		return nil
+	}
+
+	at := [][]int{}
+	for _, k := range fewest {
+		if b.cells[k].IsSet(d) {
+			at = append(at, []int{k / Size, k % Size})
+		}
+	}
+	return at
+}
+
+var (
+	// ErrNoSolution is returned when the search is exhausted without finding a
+	// solution.
+	ErrNoSolution = errors.New("no solution")
+	// ErrInvalidBoard is returned when the board has a cell without pencilmarks.
+	ErrInvalidBoard = errors.New("invalid board")
+	// ErrContradiction is returned when the logical techniques leave the board
+	// breaking the rules.
+	ErrContradiction = errors.New("contradiction")
+)
+
+// SolveE is [Board.Solve] reporting the reason of failure. It returns
+// [ErrInvalidBoard] if a cell already has no pencilmarks before the search
+// and [ErrNoSolution] if the search finds no solution.
+func (b *Board) SolveE() error {
+	for _, c := range b.cells {
+		if c == 0 {
+			return ErrInvalidBoard
+		}
+	}
+	if !b.Solve() {
+		return ErrNoSolution
+	}
+	return nil
+}
+
+// CountSolutions counts the solutions of the sudoku, stopping once limit is
+// reached. The board is left unchanged.
+func (b *Board) CountSolutions(limit int) int {
+	cnt := 0
+	if limit <= 0 {
+		return cnt
+	}
+	for range b.Solutions() {
+		cnt++
+		if cnt >= limit {
+			break
+		}
+	}
+	return cnt
+}
+
+// Solutions iterates all solutions of the sudoku. It guesses the [Lowest] cell
+// recursively like [Board.Solve], but carries on after each solution found,
+// yielding a copy of it. A board breaking the rules, see [Board.IsValid], has
+// no solutions. The board is left unchanged.
+func (b *Board) Solutions() iter.Seq[*Board] {
+	return func(yield func(*Board) bool) {
+		if !b.IsValid() {
+			return
+		}
+		c := b.Clone()
+		if c.setSingles() {
+			c.solutions(yield, &trail{})
+		}
+	}
+}
+
+// setSingles sets the single digit cells to their digit with [Board.set],
+// dropping it from their peers. It returns false if a cell is left without
+// pencilmarks.
+func (b *Board) setSingles() bool {
+	for k := range b.cells {
+		if c := b.cells[k]; c.Single() && !b.set(k/Size, k%Size, c.Digit(), nil) {
+			return false
+		}
+	}
+	return true
+}
+
+// solutions is the implementation of [Board.Solutions], undoing the changes
+// recorded on t when backtracking. It returns false once yield returned false.
+func (b *Board) solutions(yield func(*Board) bool, t *trail) bool {
+	i, j, ok := b.Lowest()
+	if !ok {
+		return !b.Solved() || yield(b.Clone())
+	}
+
+	for d := range b.At(i, j).Digits() {
+		n := len(*t)
+
+		if b.set(i, j, d, t) && !b.solutions(yield, t) {
+			return false
+		}
+
+		b.undo(t, n)
+	}
+	return true
+}
+
+// SolveN returns up to n distinct solutions of the sudoku, each a separate
+// board. The board is left unchanged.
+func (b *Board) SolveN(n int) []*Board {
+	solutions := []*Board{}
+	if n <= 0 {
+		return solutions
+	}
+	for s := range b.Solutions() {
+		solutions = append(solutions, s)
+		if len(solutions) >= n {
+			break
+		}
+	}
+	return solutions
+}
+
+// HasUniqueSolution determines whether the sudoku has exactly one solution.
+// An unsolvable board returns false. The board is left unchanged.
+func (b *Board) HasUniqueSolution() bool {
+	return b.Clone().CountSolutions(2) == 1
+}
+
+// Print prints the sudoku board to the standard output. (all pencilmarks for
+// all cells.)
 func (b *Board) Print() {
+	b.Fprint(os.Stdout)
+}
+
+// Fprint prints the sudoku board to w. (all pencilmarks for all cells.)
+func (b *Board) Fprint(w io.Writer) {
+	border := rule("-", len(All().String()))
 	for i := range Size {
-		if i%3 == 0 {
-			fmt.Printf("|-------------------------------|-------------------------------|-------------------------------|\n")
+		if i%boxHeight == 0 {
+			fmt.Fprint(w, border)
 		}
 		for j := range Size {
-			if j%3 == 0 {
+			if j%boxWidth == 0 {
+				fmt.Fprintf(w, "| ")
+			}
+			fmt.Fprintf(w, "%s ", b.cells[i*Size+j])
+		}
+		fmt.Fprintf(w, "|\n")
+	}
+	fmt.Fprint(w, border)
+}
+
+// rule is a horizontal line of fill across the board, with '|' at the box
+// boundaries, for printing cells of w characters each separated by a space.
+func rule(fill string, w int) string {
+	return strings.Repeat("|"+strings.Repeat(fill, boxWidth*(w+1)+1), boxHeight) + "|\n"
+}
+
+// PrintSolution prints the sudoku board to w showing the digits of single digit
+// cells and '.' for all other cells.
+func (b *Board) PrintSolution(w io.Writer) {
+	border := rule("-", 1)
+	for i := range Size {
+		if i%boxHeight == 0 {
+			fmt.Fprint(w, border)
+		}
+		for j, c := range b.Row(i) {
+			if j%boxWidth == 0 {
+				fmt.Fprintf(w, "| ")
+			}
+			if c.Single() {
+				fmt.Fprintf(w, "%c ", digitChar(c.Digit()))
+			} else {
+				fmt.Fprint(w, ". ")
+			}
+		}
+		fmt.Fprintf(w, "|\n")
+	}
+	fmt.Fprint(w, border)
+}
+
+// PrintAnnotated prints the sudoku board showing the digits of single digit
+// cells. Givens are shown in brackets to distinguish them from deductions.
+func (b *Board) PrintAnnotated() {
+	border := rule("-", 3)
+	for i := range Size {
+		if i%boxHeight == 0 {
+			fmt.Print(border)
+		}
+		for j, c := range b.Row(i) {
+			if j%boxWidth == 0 {
 				fmt.Printf("| ")
 			}
-			fmt.Printf("%s ", b[i*Size+j])
+			switch {
+			case b.given[i*Size+j]:
+				fmt.Printf("[%c] ", digitChar(c.Digit()))
+			case c.Single():
+				fmt.Printf(" %c  ", digitChar(c.Digit()))
+			default:
+				fmt.Print(" .  ")
+			}
 		}
 		fmt.Printf("|\n")
 	}
-	fmt.Printf("|-------------------------------|-------------------------------|-------------------------------|\n")
+	fmt.Print(border)
+}
+
+// PrintCandidates prints the sudoku board to w showing the pencilmarks of each
+// cell laid out in a small grid of the shape of a box.
+func (b *Board) PrintCandidates(w io.Writer) {
+	border, spacer := rule("-", boxWidth), rule(" ", boxWidth)
+	for i := range Size {
+		if i%boxHeight == 0 {
+			fmt.Fprint(w, border)
+		} else {
+			fmt.Fprint(w, spacer)
+		}
+		for line := range boxHeight {
+			for j, c := range b.Row(i) {
+				if j%boxWidth == 0 {
+					fmt.Fprint(w, "| ")
+				}
+				for d := uint(line*boxWidth + 1); d <= uint((line+1)*boxWidth); d++ {
+					if c.IsSet(d) {
+						fmt.Fprintf(w, "%c", digitChar(d))
+					} else {
+						fmt.Fprint(w, " ")
+					}
+				}
+				fmt.Fprint(w, " ")
+			}
+			fmt.Fprint(w, "|\n")
+		}
+	}
+	fmt.Fprint(w, border)
 }
 
+// String is the 81 character representation of the board, row by row. Single
+// digit cells are shown as their digit, all other cells as '.'. [ParseString]
+// reads it back, but the cells resolved while setting the givens of a parsed
+// board are single digit too, so they are printed along with the givens.
+func (b *Board) String() string {
+	s := strings.Builder{}
+	for _, c := range b.cells {
+		if c.Single() {
+			s.WriteByte(digitChar(c.Digit()))
+		} else {
+			s.WriteString(".")
+		}
+	}
+	return s.String()
+}
+
+// WriteTo writes the [Board.String] form of the board followed by a newline to
+// w, the form read back by [ParsePuzzles]. It implements [io.WriterTo].
+func (b *Board) WriteTo(w io.Writer) (int64, error) {
+	n, err := io.WriteString(w, b.String()+"\n")
+	return int64(n), err
+}
+
+// countLimit is the number of solutions the -count flag counts up to.
+const countLimit = 1000
+
+// main solves the puzzle given as the argument, or read from the standard input
+// if there is no argument, and prints the solution. With -count it prints the
+// number of solutions instead.
 func main() {
-	b := EmptyBoard()
+	count := flag.Bool("count", false, fmt.Sprintf("print the number of solutions (up to %d) instead of a solution", countLimit))
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [-count] [puzzle]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	puzzle := flag.Arg(0)
+	if flag.NArg() == 0 {
+		in, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			fail(err)
+		}
+		puzzle = string(in)
+	}
+
+	b, err := ParseString(strings.TrimSpace(puzzle))
+	if err != nil {
+		fail(err)
+	}
+
+	if *count {
+		fmt.Println(b.CountSolutions(countLimit))
+		return
+	}
+
+	if !b.Solve() {
+		fail(ErrNoSolution)
+	}
+	b.PrintSolution(os.Stdout)
+}
 
-	// https://sudoku2.com/play-the-hardest-sudoku-in-the-world/
-	b.Set(0, 0, 8)
-	b.Set(1, 2, 3)
-	b.Set(1, 3, 6)
-	b.Set(2, 1, 7)
-	b.Set(2, 4, 9)
-	b.Set(2, 6, 2)
-	b.Set(3, 1, 5)
-	b.Set(3, 5, 7)
-	b.Set(4, 4, 4)
-	b.Set(4, 5, 5)
-	b.Set(4, 6, 7)
-	b.Set(5, 3, 1)
-	b.Set(5, 7, 3)
-	b.Set(6, 2, 1)
-	b.Set(6, 7, 6)
-	b.Set(6, 8, 8)
-	b.Set(7, 2, 8)
-	b.Set(7, 3, 5)
-	b.Set(7, 7, 1)
-	b.Set(8, 1, 9)
-	b.Set(8, 6, 4)
-
-	b.Solve()
-	b.Print()
+// fail prints err to the standard error and exits with a non-zero status.
+func fail(err error) {
+	fmt.Fprintf(os.Stderr, "%s: %v\n", os.Args[0], err)
+	os.Exit(1)
 }
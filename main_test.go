@@ -0,0 +1,342 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLowestTie(t *testing.T) {
+	b := EmptyBoard()
+	for d := uint(3); d <= Size; d++ {
+		b.At(0, 3).Drop(d)
+		b.At(1, 0).Drop(d)
+	}
+
+	i, j, ok := b.Lowest()
+	if !ok || i != 0 || j != 3 {
+		t.Errorf("Lowest() = %d, %d, %v, expected 0, 3, true", i, j, ok)
+	}
+}
+
+func TestSolutionsEmptyCell(t *testing.T) {
+	b := EmptyBoard()
+	b.At(4%Size, 4%Size).Clear()
+
+	if n := b.CountSolutions(2); n != 0 {
+		t.Errorf("CountSolutions(2) = %d, expected 0", n)
+	}
+	if s := b.SolveN(1); len(s) != 0 {
+		t.Errorf("SolveN(1) returned %d solutions, expected 0", len(s))
+	}
+}
+
+func TestSolveByDigitEmptyCell(t *testing.T) {
+	b := EmptyBoard()
+	b.At(4%Size, 4%Size).Clear()
+
+	if b.SolveByDigit() {
+		t.Error("SolveByDigit() solved a board with an empty cell")
+	}
+}
+
+func TestEliminateOutOfRange(t *testing.T) {
+	b := EmptyBoard()
+	for _, tc := range []struct {
+		i, j int
+		d    uint
+	}{{Size, 0, 1}, {0, -1, 1}, {0, 0, 0}, {0, 0, Size + 1}} {
+		if changed, contradiction := b.Eliminate(tc.i, tc.j, tc.d); changed || contradiction {
+			t.Errorf("Eliminate(%d, %d, %d) = %v, %v, expected false, false", tc.i, tc.j, tc.d, changed, contradiction)
+		}
+	}
+	if !b.Equal(EmptyBoard()) {
+		t.Error("Eliminate() changed the board")
+	}
+}
+
+func TestSolveStepsEmptyCell(t *testing.T) {
+	b := EmptyBoard()
+	b.At(4%Size, 4%Size).Clear()
+
+	var last *Board
+	for step := range b.SolveSteps() {
+		last = step
+	}
+	if last == nil || last.Solved() {
+		t.Error("SolveSteps() didn't end with the unsolved board")
+	}
+}
+
+// only9x9 skips the tests of hand made 9x9 boards on the other sizes.
+func only9x9(t *testing.T) {
+	t.Helper()
+	if Size != 9 {
+		t.Skip("needs the 9x9 board")
+	}
+}
+
+// keep drops all pencilmarks but ds from the cell in the ith row jth column.
+func keep(b *Board, i, j int, ds ...uint) {
+	c := b.At(i, j).Clear()
+	for _, d := range ds {
+		c.Set(d)
+	}
+}
+
+// dropExcept drops d from the cells of row i, except from the columns js.
+func dropExcept(b *Board, i int, d uint, js ...int) {
+	for j := range Size {
+		if !slices.Contains(js, j) {
+			b.At(i, j).Drop(d)
+		}
+	}
+}
+
+func TestTechniques(t *testing.T) {
+	only9x9(t)
+
+	// pencilmark is the pencilmark D of the cell in the Ith row Jth column.
+	type pencilmark struct {
+		I, J int
+		D    uint
+	}
+
+	tests := []struct {
+		technique Technique
+		setup     func(b *Board)
+		dropped   []pencilmark
+		kept      []pencilmark
+	}{
+		{
+			NakedSingles,
+			func(b *Board) {
+				keep(b, 0, 0, 5, 6)
+				keep(b, 0, 1, 5)
+			},
+			[]pencilmark{{0, 8, 5}, {8, 1, 5}, {1, 0, 5}, {0, 0, 5}},
+			// (0, 0) is left single digit for the next pass of naked singles.
+			[]pencilmark{{0, 0, 6}, {0, 2, 6}},
+		},
+		{
+			HiddenSingles,
+			func(b *Board) { dropExcept(b, 0, 7, 3) },
+			[]pencilmark{{0, 3, 1}, {0, 3, 9}},
+			[]pencilmark{{0, 3, 7}, {0, 4, 1}},
+		},
+		{
+			NakedPairs,
+			func(b *Board) {
+				keep(b, 0, 0, 1, 2)
+				keep(b, 0, 1, 1, 2)
+			},
+			[]pencilmark{{0, 5, 1}, {0, 5, 2}, {1, 2, 1}},
+			[]pencilmark{{0, 0, 1}, {0, 1, 2}, {0, 5, 3}, {1, 5, 1}},
+		},
+		{
+			NakedTriples,
+			func(b *Board) {
+				keep(b, 0, 0, 1, 2)
+				keep(b, 0, 4, 2, 3)
+				keep(b, 0, 8, 1, 3)
+			},
+			[]pencilmark{{0, 1, 1}, {0, 5, 2}, {0, 7, 3}},
+			[]pencilmark{{0, 4, 2}, {0, 5, 4}, {1, 1, 1}},
+		},
+		{
+			NakedQuads,
+			func(b *Board) {
+				keep(b, 0, 0, 1, 2)
+				keep(b, 0, 3, 2, 3)
+				keep(b, 0, 6, 3, 4)
+				keep(b, 0, 8, 1, 4)
+			},
+			[]pencilmark{{0, 4, 1}, {0, 4, 4}, {0, 1, 3}},
+			[]pencilmark{{0, 4, 5}, {1, 0, 1}},
+		},
+		{
+			HiddenPairs,
+			func(b *Board) {
+				dropExcept(b, 0, 1, 0, 4)
+				dropExcept(b, 0, 2, 0, 4)
+			},
+			[]pencilmark{{0, 0, 3}, {0, 4, 9}},
+			[]pencilmark{{0, 0, 1}, {0, 4, 2}, {0, 1, 3}},
+		},
+		{
+			HiddenTriples,
+			func(b *Board) {
+				dropExcept(b, 0, 1, 0, 4)
+				dropExcept(b, 0, 2, 4, 8)
+				dropExcept(b, 0, 3, 0, 8)
+			},
+			[]pencilmark{{0, 0, 9}, {0, 4, 5}, {0, 8, 4}},
+			[]pencilmark{{0, 0, 1}, {0, 0, 3}, {0, 8, 2}, {0, 1, 9}},
+		},
+		{
+			PointingPairs,
+			func(b *Board) {
+				dropExcept(b, 1, 1, 3, 4, 5, 6, 7, 8)
+				dropExcept(b, 2, 1, 3, 4, 5, 6, 7, 8)
+			},
+			[]pencilmark{{0, 5, 1}, {0, 8, 1}},
+			[]pencilmark{{0, 0, 1}, {1, 5, 1}},
+		},
+		{
+			XWing,
+			func(b *Board) {
+				dropExcept(b, 0, 1, 1, 7)
+				dropExcept(b, 4, 1, 1, 7)
+			},
+			[]pencilmark{{2, 1, 1}, {8, 7, 1}},
+			[]pencilmark{{0, 1, 1}, {4, 7, 1}, {2, 2, 1}},
+		},
+		{
+			Swordfish,
+			func(b *Board) {
+				dropExcept(b, 0, 1, 1, 4)
+				dropExcept(b, 3, 1, 4, 7)
+				dropExcept(b, 6, 1, 1, 7)
+			},
+			[]pencilmark{{2, 4, 1}, {8, 1, 1}, {5, 7, 1}},
+			[]pencilmark{{0, 4, 1}, {6, 7, 1}, {2, 3, 1}},
+		},
+		{
+			YWing,
+			func(b *Board) {
+				keep(b, 0, 0, 1, 2)
+				keep(b, 0, 4, 1, 3)
+				keep(b, 4, 0, 2, 3)
+			},
+			[]pencilmark{{4, 4, 3}},
+			[]pencilmark{{4, 5, 3}, {4, 4, 1}},
+		},
+		{
+			SimpleColoring,
+			func(b *Board) {
+				// The chain (0, 0) - (0, 4) - (4, 4) and (0, 0) - (5, 0) of the
+				// 1 colors (0, 0) and (4, 4) against (0, 4) and (5, 0).
+				dropExcept(b, 0, 1, 0, 4)
+				for i := range Size {
+					if i != 0 && i != 4 {
+						b.At(i, 4).Drop(1)
+					}
+					if i != 0 && i != 5 {
+						b.At(i, 0).Drop(1)
+					}
+				}
+			},
+			[]pencilmark{{4, 1, 1}},
+			[]pencilmark{{0, 0, 1}, {0, 4, 1}, {4, 4, 1}, {5, 0, 1}, {4, 7, 1}},
+		},
+		{
+			WXYZWing,
+			func(b *Board) {
+				keep(b, 0, 0, 1, 2, 3)
+				keep(b, 0, 4, 1, 4)
+				keep(b, 1, 1, 2, 4)
+				keep(b, 2, 2, 3, 4)
+			},
+			[]pencilmark{{0, 1, 4}, {0, 2, 4}},
+			[]pencilmark{{0, 3, 4}, {1, 0, 4}, {0, 4, 4}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.technique.Name(), func(t *testing.T) {
+			b := EmptyBoard()
+			tc.setup(b)
+
+			if !tc.technique.Apply(b) {
+				t.Fatal("Apply() = false, expected true")
+			}
+			for _, p := range tc.dropped {
+				if b.At(p.I, p.J).IsSet(p.D) {
+					t.Errorf("pencilmark %d at row %d column %d not dropped", p.D, p.I, p.J)
+				}
+			}
+			for _, p := range tc.kept {
+				if !b.At(p.I, p.J).IsSet(p.D) {
+					t.Errorf("pencilmark %d at row %d column %d dropped", p.D, p.I, p.J)
+				}
+			}
+		})
+	}
+}
+
+func TestBUG(t *testing.T) {
+	only9x9(t)
+
+	// Swapping the digits a and b of a rectangle of a solution, spanning two
+	// boxes, gives another solution. With both digits in the four cells the
+	// board is a bivalue universal grave, and a third digit in one of them is
+	// the BUG+1 that resolves it.
+	s := Generate(1)
+	for r1 := range Size {
+		for r2 := r1 + 1; r2 < Size && r2/boxHeight == r1/boxHeight; r2++ {
+			for c1 := range Size {
+				for c2 := (c1/boxWidth + 1) * boxWidth; c2 < Size; c2++ {
+					a, b := s.At(r1, c1).Digit(), s.At(r1, c2).Digit()
+					if s.At(r2, c1).Digit() != b || s.At(r2, c2).Digit() != a {
+						continue
+					}
+
+					grave := s.Clone()
+					for _, xy := range [][2]int{{r1, c1}, {r1, c2}, {r2, c1}, {r2, c2}} {
+						keep(grave, xy[0], xy[1], a, b)
+					}
+					if grave.BUG() {
+						t.Error("BUG() = true on a bivalue universal grave without the +1")
+					}
+
+					e := uint(1)
+					for e == a || e == b {
+						e++
+					}
+					grave.At(r1, c1).Set(e)
+					if !grave.BUG() {
+						t.Fatal("BUG() = false, expected true")
+					}
+					if d, ok := grave.Get(r1, c1); !ok || d != e {
+						t.Errorf("BUG() set row %d column %d to %v, expected %d", r1, c1, grave.At(r1, c1), e)
+					}
+					return
+				}
+			}
+		}
+	}
+	t.Fatal("no rectangle found")
+}
+
+func TestTechniquesSound(t *testing.T) {
+	only9x9(t)
+
+	for seed := range int64(20) {
+		puzzle := GeneratePuzzle(seed)
+		solution := puzzle.Clone()
+		if !solution.SolveDLX() {
+			t.Fatalf("GeneratePuzzle(%d) is not solvable", seed)
+		}
+
+		// sound reports the pencilmarks of the solution dropped from b.
+		sound := func(b *Board, technique string) {
+			for k, c := range b.cells {
+				if d := solution.cells[k].Digit(); !c.IsSet(d) {
+					t.Fatalf("%s dropped %d at row %d column %d of GeneratePuzzle(%d)", technique, d, k/Size, k%Size, seed)
+				}
+			}
+		}
+
+		b := puzzle.Clone()
+		for tech := range b.replay() {
+			sound(b, tech.Name())
+		}
+
+		for _, tech := range techniques {
+			b := puzzle.Clone()
+			b.FillSingles()
+			for tech.Apply(b) {
+				sound(b, tech.Name())
+			}
+		}
+	}
+}
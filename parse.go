@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+	"unicode"
+)
+
+// digitChars are the characters of the digits from 1, beyond 9 continuing with
+// letters for boards larger than 9x9.
+const digitChars = "123456789ABCDEFG"
+
+// digitChar is the character of the digit d.
+func digitChar(d uint) byte {
+	return digitChars[d-1]
+}
+
+// charDigit is the digit of the character ch, or false if ch is not a digit of
+// the board size. Letters are accepted in either case.
+func charDigit(ch rune) (uint, bool) {
+	d := strings.IndexRune(digitChars[:Size], unicode.ToUpper(ch))
+	return uint(d + 1), d >= 0
+}
+
+// ParseString parses a sudoku from its 81 character form. The string is read
+// left to right, top to bottom. Digits 1-9, followed by the letters A-G for
+// boards larger than 9x9, are givens, '.' or '0' are empty cells. Givens are
+// set with [Board.TrySet] and marked as givens. It returns an error if the
+// string is malformed or a given contradicts an earlier one.
+func ParseString(s string) (*Board, error) {
+	return ParseStringWithBlanks(s, ".0")
+}
+
+// ParseStringWithBlanks is [ParseString] with any of the characters of blanks
+// meaning an empty cell.
+func ParseStringWithBlanks(s string, blanks string) (*Board, error) {
+	chs := []rune(s)
+	if len(chs) != Size*Size {
+		return nil, fmt.Errorf("puzzle has length %d, expected %d", len(chs), Size*Size)
+	}
+
+	b := EmptyBoard()
+	for k, ch := range chs {
+		i, j := k/Size, k%Size
+
+		if strings.ContainsRune(blanks, ch) {
+			continue
+		}
+
+		d, ok := charDigit(ch)
+		if !ok {
+			return nil, fmt.Errorf("invalid character %q at row %d column %d", ch, i, j)
+		}
+		if !b.At(i, j).IsSet(d) {
+			return nil, fmt.Errorf("given %d at row %d column %d contradicts earlier givens", d, i, j)
+		}
+		if err := b.TrySet(i, j, d); err != nil {
+			return nil, err
+		}
+		b.given[k] = true
+	}
+	return b, nil
+}
+
+// ParseGrid parses a sudoku laid out as a grid, like the output of
+// [Board.PrintSolution]. Whitespace, the border characters '|', '-' and '+'
+// and the brackets around givens are dropped, the rest is parsed with
+// [ParseString].
+func ParseGrid(s string) (*Board, error) {
+	return ParseString(strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || strings.ContainsRune("|-+[]", r) {
+			return -1
+		}
+		return r
+	}, s))
+}
+
+// ParseClueList builds a sudoku from a list of clues, each the digit D in the
+// Ith row Jth column. The clues are set with [Board.TrySet] in order and marked
+// as givens. It returns an error if a digit is out of range or a clue
+// contradicts an earlier one.
+func ParseClueList(clues []struct {
+	I, J int
+	D    uint
+}) (*Board, error) {
+	b := EmptyBoard()
+	for _, clue := range clues {
+		if clue.D < 1 || clue.D > Size {
+			return nil, fmt.Errorf("digit %d at row %d column %d out of range", clue.D, clue.I, clue.J)
+		}
+		if err := b.TrySet(clue.I, clue.J, clue.D); err != nil {
+			return nil, err
+		}
+		b.given[clue.I*Size+clue.J] = true
+	}
+	return b, nil
+}
+
+// ParsePuzzles iterates the puzzles read from r, one [ParseString] form per
+// line. Blank lines and lines starting with '#' are skipped. Lines that fail to
+// parse, as well as read errors, are yielded as errors.
+func ParsePuzzles(r io.Reader) iter.Seq2[*Board, error] {
+	return func(yield func(*Board, error) bool) {
+		s := bufio.NewScanner(r)
+		for n := 1; s.Scan(); n++ {
+			line := strings.TrimSpace(s.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+
+			b, err := ParseString(line)
+			if err != nil {
+				err = fmt.Errorf("line %d: %w", n, err)
+			}
+			if !yield(b, err) {
+				return
+			}
+		}
+		if err := s.Err(); err != nil {
+			yield(nil, err)
+		}
+	}
+}
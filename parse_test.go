@@ -0,0 +1,14 @@
+//go:build !size4 && !size16
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseStringContradiction(t *testing.T) {
+	if _, err := ParseString("3456789.." + "......2.." + strings.Repeat(".", 63)); err == nil {
+		t.Error("ParseString() accepted givens leaving a cell without pencilmarks")
+	}
+}
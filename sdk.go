@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ParseSDK parses a sudoku in the SadMan .sdk format from r: one line of
+// characters per row in the [ParseString] notation. Blank lines and lines
+// starting with '#' are skipped, reading stops after the last row. It returns an
+// error if a row is malformed, there are too few rows or a given contradicts an
+// earlier one.
+func ParseSDK(r io.Reader) (*Board, error) {
+	return parseRows(r, func(line string) (string, bool) {
+		return line, line != "" && !strings.HasPrefix(line, "#")
+	})
+}
+
+// parseRows parses a sudoku written one row per line in r. row maps a line,
+// with surrounding whitespace trimmed, to the row in the [ParseString]
+// notation, or returns false if the line is to be skipped. Reading stops after
+// the last row.
+func parseRows(r io.Reader, row func(line string) (string, bool)) (*Board, error) {
+	grid := strings.Builder{}
+	rows := 0
+
+	s := bufio.NewScanner(r)
+	for n := 1; rows < Size && s.Scan(); n++ {
+		line, ok := row(strings.TrimSpace(s.Text()))
+		if !ok {
+			continue
+		}
+		if len(line) != Size {
+			return nil, fmt.Errorf("line %d: row has length %d, expected %d", n, len(line), Size)
+		}
+		grid.WriteString(line)
+		rows++
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if rows != Size {
+		return nil, fmt.Errorf("puzzle has %d rows, expected %d", rows, Size)
+	}
+
+	return ParseString(grid.String())
+}
+
+// WriteSDK writes the board to w in the format read by [ParseSDK]. Cells that
+// are not single digit are written as '.'.
+func (b *Board) WriteSDK(w io.Writer) error {
+	s := b.String()
+	sdk := strings.Builder{}
+	for i := range Size {
+		sdk.WriteString(s[i*Size : (i+1)*Size])
+		sdk.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, sdk.String())
+	return err
+}
@@ -0,0 +1,9 @@
+//go:build !size4 && !size16
+
+package main
+
+// The dimensions of a box. The classic 9x9 sudoku has 3x3 boxes.
+const (
+	boxWidth  = 3
+	boxHeight = 3
+)
@@ -0,0 +1,9 @@
+//go:build size16
+
+package main
+
+// The dimensions of a box. The 16x16 sudoku has 4x4 boxes.
+const (
+	boxWidth  = 4
+	boxHeight = 4
+)
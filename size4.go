@@ -0,0 +1,9 @@
+//go:build size4
+
+package main
+
+// The dimensions of a box. The 4x4 sudoku has 2x2 boxes.
+const (
+	boxWidth  = 2
+	boxHeight = 2
+)
@@ -0,0 +1,41 @@
+//go:build !size4 && !size16
+
+package main
+
+import "testing"
+
+// hardest is https://sudoku2.com/play-the-hardest-sudoku-in-the-world/
+const hardest = "8........" +
+	"..36....." +
+	".7..9.2.." +
+	".5...7..." +
+	"....457.." +
+	"...1...3." +
+	"..1....68" +
+	"..85...1." +
+	".9....4.."
+
+func benchmarkSolve(b *testing.B, solve func(*Board) bool) {
+	puzzle, err := ParseString(hardest)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for range b.N {
+		if !solve(puzzle.Clone()) {
+			b.Fatal("not solved")
+		}
+	}
+}
+
+func BenchmarkSolve(b *testing.B) {
+	benchmarkSolve(b, (*Board).Solve)
+}
+
+func BenchmarkSolveDLX(b *testing.B) {
+	benchmarkSolve(b, (*Board).SolveDLX)
+}
+
+func BenchmarkSolveByDigit(b *testing.B) {
+	benchmarkSolve(b, (*Board).SolveByDigit)
+}
@@ -0,0 +1,35 @@
+package main
+
+import "context"
+
+// Solver is a solver applying a configurable set of logical techniques.
+type Solver struct {
+	techniques []Technique
+
+	// Guess is whether [Solver.Solve] falls back to guessing when the
+	// techniques don't solve the board.
+	Guess bool
+}
+
+// NewSolver is a [Solver] applying the techniques ts, such as [HiddenSingles],
+// cheapest first. The built-in techniques leave the cells they resolve to
+// [NakedSingles], so ts normally starts with it. It doesn't guess.
+func NewSolver(ts ...Technique) *Solver {
+	return &Solver{techniques: ts}
+}
+
+// Solve applies the techniques of the solver to b until none of them makes
+// progress, like [Board.Propagate]. If b is not solved by then and the solver
+// guesses, it carries on guessing like [Board.Solve]. It returns whether b is
+// solved.
+func (s *Solver) Solve(b *Board) bool {
+	if b.propagate(s.techniques) {
+		return true
+	}
+	if !s.Guess {
+		return false
+	}
+
+	solved, _ := b.search(context.Background(), &stats{})
+	return solved
+}
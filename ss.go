@@ -0,0 +1,43 @@
+package main
+
+import (
+	"io"
+	"strings"
+)
+
+// WriteSS writes the board to w in the Simple Sudoku .ss format: one line per
+// row in the [ParseString] notation with '|' between the boxes and rulers of
+// '-' between the rows of boxes. Cells that are not single digit are written
+// as '.'.
+func (b *Board) WriteSS(w io.Writer) error {
+	s := b.String()
+	ss := strings.Builder{}
+	for i := range Size {
+		if i != 0 && i%boxHeight == 0 {
+			ss.WriteString(strings.Repeat("-", Size+boxHeight-1) + "\n")
+		}
+		for j := range Size {
+			if j != 0 && j%boxWidth == 0 {
+				ss.WriteString("|")
+			}
+			ss.WriteByte(s[i*Size+j])
+		}
+		ss.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, ss.String())
+	return err
+}
+
+// ParseSS parses a sudoku in the format written by [Board.WriteSS] from r. The
+// '|' separators are dropped, and blank lines, rulers and lines starting with
+// '#' are skipped. It returns an error if a row is malformed, there are too few
+// rows or a given contradicts an earlier one.
+func ParseSS(r io.Reader) (*Board, error) {
+	return parseRows(r, func(line string) (string, bool) {
+		if line == "" || strings.HasPrefix(line, "#") || strings.Trim(line, "-+") == "" {
+			return "", false
+		}
+		return strings.ReplaceAll(line, "|", ""), true
+	})
+}
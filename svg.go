@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// cellPx is the size of a cell in the SVG rendering in pixels.
+const cellPx = 50
+
+// SVG writes an SVG image of the board to w. Single digit cells show their
+// digit, other cells show their pencilmarks in a small grid of the shape of a
+// box.
+func (b *Board) SVG(w io.Writer) error {
+	const size = Size * cellPx
+
+	s := strings.Builder{}
+	fmt.Fprintf(&s, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="-2 -2 %d %d">`+"\n", size+4, size+4, size+4, size+4)
+	fmt.Fprintf(&s, `<rect x="0" y="0" width="%d" height="%d" fill="white"/>`+"\n", size, size)
+
+	for n := 0; n <= Size; n++ {
+		width := 1
+		if n%boxWidth == 0 {
+			width = 3
+		}
+		fmt.Fprintf(&s, `<line x1="%d" y1="0" x2="%d" y2="%d" stroke="black" stroke-width="%d"/>`+"\n", n*cellPx, n*cellPx, size, width)
+
+		width = 1
+		if n%boxHeight == 0 {
+			width = 3
+		}
+		fmt.Fprintf(&s, `<line x1="0" y1="%d" x2="%d" y2="%d" stroke="black" stroke-width="%d"/>`+"\n", n*cellPx, size, n*cellPx, width)
+	}
+
+	for i := range Size {
+		for j, c := range b.Row(i) {
+			x, y := j*cellPx, i*cellPx
+			if c.Single() {
+				fmt.Fprintf(&s, `<text x="%d" y="%d" font-family="sans-serif" font-size="%d" text-anchor="middle" dominant-baseline="central">%c</text>`+"\n",
+					x+cellPx/2, y+cellPx/2, cellPx*2/3, digitChar(c.Digit()))
+				continue
+			}
+			for d := range c.Digits() {
+				col, row := int(d-1)%boxWidth, int(d-1)/boxWidth
+				fmt.Fprintf(&s, `<text x="%d" y="%d" font-family="sans-serif" font-size="%d" fill="gray" text-anchor="middle" dominant-baseline="central">%c</text>`+"\n",
+					x+(2*col+1)*cellPx/(2*boxWidth), y+(2*row+1)*cellPx/(2*boxHeight), cellPx/(boxHeight+1), digitChar(d))
+			}
+		}
+	}
+	s.WriteString("</svg>\n")
+
+	_, err := io.WriteString(w, s.String())
+	return err
+}
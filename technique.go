@@ -0,0 +1,1076 @@
+package main
+
+import (
+	"iter"
+	"math/bits"
+	"slices"
+)
+
+// Technique is a logical technique. Apply applies it to the board and returns
+// whether it changed the board, Name is the name of the technique.
+type Technique interface {
+	Apply(*Board) bool
+	Name() string
+}
+
+// NewTechnique is a [Technique] of the given name applying apply.
+func NewTechnique(name string, apply func(*Board) bool) Technique {
+	return technique{name, apply, nil}
+}
+
+// technique is the [Technique] built by [NewTechnique]. The built-in
+// techniques also have steps, iterating their deductions.
+type technique struct {
+	name  string
+	apply func(*Board) bool
+	steps func(*Board) iter.Seq[deduction]
+}
+
+// newTechnique is a built-in [Technique] of the given name applying the
+// deductions of steps.
+func newTechnique(name string, steps func(*Board) iter.Seq[deduction]) Technique {
+	return technique{name, func(b *Board) bool { return b.deduce(steps(b)) }, steps}
+}
+
+// Apply applies the technique to b.
+func (t technique) Apply(b *Board) bool {
+	return t.apply(b)
+}
+
+// Name is the name of the technique.
+func (t technique) Name() string {
+	return t.name
+}
+
+// The built-in techniques, applying the methods of [Board] of the same name.
+var (
+	NakedSingles   = newTechnique("naked single", (*Board).nakedSingles)
+	HiddenSingles  = newTechnique("hidden single", (*Board).hiddenSingles)
+	NakedPairs     = newTechnique("naked pair", func(b *Board) iter.Seq[deduction] { return b.nakedSubsets(2) })
+	NakedTriples   = newTechnique("naked triple", func(b *Board) iter.Seq[deduction] { return b.nakedSubsets(3) })
+	NakedQuads     = newTechnique("naked quad", func(b *Board) iter.Seq[deduction] { return b.nakedSubsets(4) })
+	HiddenPairs    = newTechnique("hidden pair", func(b *Board) iter.Seq[deduction] { return b.hiddenSubsets(2) })
+	HiddenTriples  = newTechnique("hidden triple", func(b *Board) iter.Seq[deduction] { return b.hiddenSubsets(3) })
+	PointingPairs  = newTechnique("pointing pair", (*Board).pointingPairs)
+	XWing          = newTechnique("x-wing", func(b *Board) iter.Seq[deduction] { return b.fish(2) })
+	YWing          = newTechnique("y-wing", (*Board).yWing)
+	Swordfish      = newTechnique("swordfish", func(b *Board) iter.Seq[deduction] { return b.fish(3) })
+	SimpleColoring = newTechnique("simple coloring", (*Board).simpleColoring)
+	WXYZWing       = newTechnique("wxyz-wing", (*Board).wxyzWing)
+	BUG            = newTechnique("bivalue universal grave", (*Board).bug)
+)
+
+// deduction is a step of a technique: with place the cell in the ith row jth
+// column is set to the digit d, otherwise the pencilmark d is dropped from it.
+type deduction struct {
+	i, j  int
+	d     uint
+	place bool
+}
+
+// deduce applies the deductions of steps. The techniques look at the board as
+// it's changed by their earlier deductions, so each deduction is applied
+// before the next one is taken. It returns whether any deduction was applied.
+func (b *Board) deduce(steps iter.Seq[deduction]) bool {
+	changed := false
+	for s := range steps {
+		b.apply(s)
+		changed = true
+	}
+	return changed
+}
+
+// apply applies the deduction s. Unlike [Board.Set] and [Board.Eliminate] it
+// doesn't resolve the cells left single digit, that is up to
+// [Board.NakedSingles].
+func (b *Board) apply(s deduction) {
+	k := s.i*Size + s.j
+	if !s.place {
+		b.cells[k].Drop(s.d)
+		return
+	}
+
+	b.cells[k].Clear().Set(s.d)
+	for _, p := range peers[k] {
+		b.cells[p].Drop(s.d)
+	}
+}
+
+// deductions iterates the deductions of the built-in technique t. It iterates
+// none for the techniques of [NewTechnique].
+func (b *Board) deductions(t Technique) iter.Seq[deduction] {
+	if bt, ok := t.(technique); ok && bt.steps != nil {
+		return bt.steps(b)
+	}
+	return func(func(deduction) bool) {}
+}
+
+// put yields the deduction setting the cell in the ith row jth column to d,
+// unless the cell is already single digit. It returns false once yield did.
+func (b *Board) put(i, j int, d uint, yield func(deduction) bool) bool {
+	return b.At(i, j).Single() || yield(deduction{i, j, d, true})
+}
+
+// drop yields the deduction dropping the pencilmark d from the cell in the ith
+// row jth column, unless d is not a pencilmark of the cell. It returns false
+// once yield did.
+func (b *Board) drop(i, j int, d uint, yield func(deduction) bool) bool {
+	return !b.At(i, j).IsSet(d) || yield(deduction{i, j, d, false})
+}
+
+// techniques are the logical techniques applied by [Board.Propagate], cheapest
+// first.
+var techniques = []Technique{NakedSingles, HiddenSingles, NakedPairs, HiddenPairs, PointingPairs, NakedTriples, HiddenTriples, NakedQuads, XWing, YWing, Swordfish, SimpleColoring, WXYZWing, BUG}
+
+// singles are the techniques of [Board.FillSingles].
+var singles = []Technique{NakedSingles, HiddenSingles}
+
+// tier is a difficulty level along with the techniques a puzzle of the level
+// requires.
+type tier struct {
+	name       string
+	techniques []Technique
+}
+
+// tiers are the difficulty levels, weakest first.
+var tiers = []tier{
+	{"easy", singles},
+	{"medium", []Technique{NakedSingles, HiddenSingles, NakedPairs, HiddenPairs, PointingPairs, NakedTriples, HiddenTriples, NakedQuads}},
+	{"hard", techniques},
+}
+
+// Propagate applies the logical techniques until none of them makes progress.
+// After each successful technique it starts over with the cheapest one. It
+// returns whether the board is solved.
+func (b *Board) Propagate() bool {
+	return b.propagate(techniques)
+}
+
+// PropagateE is [Board.Propagate] stopping once the board breaks the rules, see
+// [Board.IsValid], in which case it returns [ErrContradiction].
+func (b *Board) PropagateE() (solved bool, err error) {
+	return b.propagateE(techniques)
+}
+
+// SolveLogical solves the sudoku without guessing, applying [Board.Propagate].
+// It returns whether the board is solved. If it isn't, the board is left with
+// the deductions made.
+func (b *Board) SolveLogical() bool {
+	return b.Propagate()
+}
+
+// FillSingles applies only naked and hidden singles until they make no more
+// progress. It returns the number of cells filled.
+func (b *Board) FillSingles() int {
+	filled := b.Filled()
+	b.propagate(singles)
+	return b.Filled() - filled
+}
+
+// Difficulty rates the sudoku as "easy", "medium" or "hard" by the weakest set
+// of techniques that solves it without guessing, or "brute" if guessing is
+// needed. The board is left unchanged.
+func (b *Board) Difficulty() string {
+	for _, tier := range tiers {
+		if b.Clone().propagate(tier.techniques) {
+			return tier.name
+		}
+	}
+	return "brute"
+}
+
+// Hint finds the next logical step without changing the board. It returns the
+// cell in the ith row jth column that the first successful technique fills
+// with the digit d, or if the technique doesn't fill any cell, the cell it
+// first eliminates the pencilmark d from. technique is the name of the
+// technique. If no technique makes progress it returns ok false.
+func (b *Board) Hint() (i, j int, d uint, technique string, ok bool) {
+	r := b.Clone()
+	r.dropGivens()
+	for _, t := range techniques {
+		if s, ok := r.Clone().hint(t); ok {
+			return s.i, s.j, s.d, t.Name(), true
+		}
+	}
+	return 0, 0, 0, "", false
+}
+
+// hint is the first deduction of the built-in technique t filling a cell,
+// applying the deductions of t to the board until one does. If none does it's
+// the first deduction of t. If t makes no deductions it returns ok false.
+func (b *Board) hint(t Technique) (s deduction, ok bool) {
+	for step := range b.deductions(t) {
+		if step.place {
+			return step, true
+		}
+		if !ok {
+			s, ok = step, true
+		}
+		b.apply(step)
+	}
+	return s, ok
+}
+
+// dropGivens drops the digits of the givens from their peers, which the boards
+// of [Board.Givens] still have, without resolving the peers left single digit.
+func (b *Board) dropGivens() {
+	for k, g := range b.given {
+		if g {
+			b.apply(deduction{k / Size, k % Size, b.cells[k].Digit(), true})
+		}
+	}
+}
+
+// Explain names the technique that resolves the cell in the ith row jth
+// column to its digit, replaying [Board.Propagate] from the givens of the
+// board. It returns "given" for givens, the name of the technique if it sets
+// the cell to the digit, and "naked single" if the cell is left with the digit
+// as its only pencilmark, be it by setting the givens or by the deductions of
+// the techniques. If the cell is not single digit, or the techniques don't
+// resolve it to its digit, it returns ok false.
+func (b *Board) Explain(i, j int) (technique string, ok bool) {
+	d, resolved := b.Get(i, j)
+	if !resolved {
+		return "", false
+	}
+	k := i*Size + j
+	if b.given[k] {
+		return "given", true
+	}
+
+	r := b.Givens()
+	r.dropGivens()
+	if r.cells[k].Single() {
+		return "naked single", r.cells[k].Digit() == d
+	}
+
+	for t, s := range r.replay() {
+		if !r.cells[k].Single() {
+			continue
+		}
+		if s.place && s.i == i && s.j == j {
+			return t.Name(), r.cells[k].Digit() == d
+		}
+		return NakedSingles.Name(), r.cells[k].Digit() == d
+	}
+	return "", false
+}
+
+// replay is [Board.Propagate] applying the deductions of the techniques one by
+// one. It yields each deduction along with its technique after applying it.
+func (b *Board) replay() iter.Seq2[Technique, deduction] {
+	return func(yield func(Technique, deduction) bool) {
+		for applied := true; applied; {
+			applied = false
+			for _, t := range techniques {
+				for s := range b.deductions(t) {
+					b.apply(s)
+					applied = true
+					if !yield(t, s) {
+						return
+					}
+				}
+				if applied {
+					break
+				}
+			}
+		}
+	}
+}
+
+// propagate is [Board.Propagate] applying only the techniques ts.
+func (b *Board) propagate(ts []Technique) bool {
+	for b.progress(ts) {
+	}
+	return b.Solved()
+}
+
+// propagateE is [Board.PropagateE] applying only the techniques ts.
+func (b *Board) propagateE(ts []Technique) (solved bool, err error) {
+	for b.progress(ts) {
+		if !b.IsValid() {
+			return false, ErrContradiction
+		}
+	}
+	if !b.IsValid() {
+		return false, ErrContradiction
+	}
+	return b.Solved(), nil
+}
+
+// progress applies the first technique of ts that changes the board. It
+// returns whether any did.
+func (b *Board) progress(ts []Technique) bool {
+	for _, t := range ts {
+		if t.Apply(b) {
+			return true
+		}
+	}
+	return false
+}
+
+// NakedSingles resolves the single digit cells whose digit is still a
+// pencilmark of some of their peers, such as the cells the other techniques
+// leave single digit. The cells are set to their digit, dropping it from their
+// peers. It returns whether any cell was set.
+func (b *Board) NakedSingles() bool {
+	return b.deduce(b.nakedSingles())
+}
+
+// nakedSingles iterates the deductions of [Board.NakedSingles].
+func (b *Board) nakedSingles() iter.Seq[deduction] {
+	return func(yield func(deduction) bool) {
+		for k := range b.cells {
+			c := b.cells[k]
+			if !c.Single() {
+				continue
+			}
+			if slices.ContainsFunc(peers[k], func(p int) bool { return b.cells[p].IsSet(c.Digit()) }) {
+				if !yield(deduction{k / Size, k % Size, c.Digit(), true}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// HiddenSingles resolves cells using hidden singles. If a digit can go in only
+// one cell of a row, column or box, that cell is set to the digit. It returns
+// whether any cell was set.
+func (b *Board) HiddenSingles() bool {
+	return b.deduce(b.hiddenSingles())
+}
+
+// hiddenSingles iterates the deductions of [Board.HiddenSingles].
+func (b *Board) hiddenSingles() iter.Seq[deduction] {
+	return func(yield func(deduction) bool) {
+		for _, unit := range unitIndices {
+			cells := b.unitCells(unit)
+			for d := uint(1); d <= Size; d++ {
+				if CountDigit(cells[:], d) != 1 {
+					continue
+				}
+				x := slices.IndexFunc(cells[:], func(c *Cell) bool { return c.IsSet(d) })
+				if !b.put(unit[x]/Size, unit[x]%Size, d, yield) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// NakedPairs eliminates pencilmarks using naked pairs. If two cells in a row,
+// column or box have the same two pencilmarks, those two digits can't go
+// anywhere else in the unit. It returns whether any pencilmark was dropped.
+func (b *Board) NakedPairs() bool {
+	return b.deduce(b.nakedSubsets(2))
+}
+
+// NakedTriples eliminates pencilmarks using naked triples. If three cells in a
+// row, column or box have only three digits between them, those three digits
+// can't go anywhere else in the unit. The cells don't need to have all three
+// digits each. It returns whether any pencilmark was dropped.
+func (b *Board) NakedTriples() bool {
+	return b.deduce(b.nakedSubsets(3))
+}
+
+// NakedQuads eliminates pencilmarks using naked quads. If four cells in a row,
+// column or box have only four digits between them, those four digits can't
+// go anywhere else in the unit. It returns whether any pencilmark was dropped.
+func (b *Board) NakedQuads() bool {
+	return b.deduce(b.nakedSubsets(4))
+}
+
+// nakedSubsets iterates the deductions of naked subsets of n cells.
+func (b *Board) nakedSubsets(n int) iter.Seq[deduction] {
+	return func(yield func(deduction) bool) {
+		for unit := range b.units() {
+			cells := []*Cell{}
+			for _, c := range unit {
+				if cnt := c.Count(); 1 < cnt && cnt <= n {
+					cells = append(cells, c)
+				}
+			}
+
+			for subset := range combinations(len(cells), n) {
+				digits := Cell(0)
+				for _, x := range subset {
+					digits = digits.Union(*cells[x])
+				}
+				if digits.Count() != n {
+					continue
+				}
+
+				for xy, c := range unit {
+					if slices.ContainsFunc(subset, func(x int) bool { return cells[x] == c }) {
+						continue
+					}
+					for d := range digits.Digits() {
+						if !b.drop(xy[0], xy[1], d, yield) {
+							return
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// HiddenPairs eliminates pencilmarks using hidden pairs. If two digits can go
+// only in the same two cells of a row, column or box, those cells can't take
+// any other digit. It returns whether any pencilmark was dropped.
+func (b *Board) HiddenPairs() bool {
+	return b.deduce(b.hiddenSubsets(2))
+}
+
+// HiddenTriples eliminates pencilmarks using hidden triples. If three digits
+// can go only in the same three cells of a row, column or box, those cells
+// can't take any other digit. The digits don't need to be in all three cells
+// each. It returns whether any pencilmark was dropped.
+func (b *Board) HiddenTriples() bool {
+	return b.deduce(b.hiddenSubsets(3))
+}
+
+// hiddenSubsets iterates the deductions of hidden subsets of n digits.
+func (b *Board) hiddenSubsets(n int) iter.Seq[deduction] {
+	return func(yield func(deduction) bool) {
+		for _, unit := range unitIndices {
+			cells := b.unitCells(unit)
+
+			// places are the bitmasks of the unit cells where the digits can go.
+			digits, places := []uint{}, []uint{}
+			for d := uint(1); d <= Size; d++ {
+				place := uint(0)
+				for x, c := range cells {
+					if c.IsSet(d) {
+						place |= 1 << x
+					}
+				}
+				if cnt := bits.OnesCount(place); 1 < cnt && cnt <= n {
+					digits = append(digits, d)
+					places = append(places, place)
+				}
+			}
+
+			for subset := range combinations(len(digits), n) {
+				place, keep := uint(0), Cell(0)
+				for _, x := range subset {
+					place |= places[x]
+					keep.Set(digits[x])
+				}
+				if bits.OnesCount(place) != n {
+					continue
+				}
+
+				for x, c := range cells {
+					if place&(1<<x) == 0 {
+						continue
+					}
+					for d := range c.Without(keep).Digits() {
+						if !b.drop(unit[x]/Size, unit[x]%Size, d, yield) {
+							return
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// PointingPairs eliminates pencilmarks using the intersections of boxes with
+// rows and columns. If all candidates of a digit within a box lie in a single
+// row or column, the digit can't go anywhere else in that row or column.
+// Conversely, if all candidates of a digit within a row or column lie in a
+// single box, the digit can't go anywhere else in that box. It returns whether
+// any pencilmark was dropped.
+func (b *Board) PointingPairs() bool {
+	return b.deduce(b.pointingPairs())
+}
+
+// pointingPairs iterates the deductions of [Board.PointingPairs].
+func (b *Board) pointingPairs() iter.Seq[deduction] {
+	return func(yield func(deduction) bool) {
+		for d := uint(1); d <= Size; d++ {
+			for n := range Size {
+				row, col := -1, -1
+				for xy, c := range b.BoxByIndex(n) {
+					if c.IsSet(d) {
+						row, col = same(row, xy[0]), same(col, xy[1])
+					}
+				}
+				x, y := (n/boxHeight)*boxHeight, (n%boxHeight)*boxWidth
+				if row >= 0 {
+					for j := range b.Row(row) {
+						if j/boxWidth != y/boxWidth && !b.drop(row, j, d, yield) {
+							return
+						}
+					}
+				}
+				if col >= 0 {
+					for i := range b.Col(col) {
+						if i/boxHeight != x/boxHeight && !b.drop(i, col, d, yield) {
+							return
+						}
+					}
+				}
+			}
+
+			for i := range Size {
+				box := -1
+				for j, c := range b.Row(i) {
+					if c.IsSet(d) {
+						box = same(box, j/boxWidth)
+					}
+				}
+				if box >= 0 {
+					for xy := range b.Box(i, box*boxWidth) {
+						if xy[0] != i && !b.drop(xy[0], xy[1], d, yield) {
+							return
+						}
+					}
+				}
+			}
+			for j := range Size {
+				box := -1
+				for i, c := range b.Col(j) {
+					if c.IsSet(d) {
+						box = same(box, i/boxHeight)
+					}
+				}
+				if box >= 0 {
+					for xy := range b.Box(box*boxHeight, j) {
+						if xy[1] != j && !b.drop(xy[0], xy[1], d, yield) {
+							return
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// XWing eliminates pencilmarks using X-Wings. If the candidates of a digit in
+// two rows lie in the same two columns, the digit can't go anywhere else in
+// those columns. The same holds with the roles of rows and columns swapped. It
+// returns whether any pencilmark was dropped.
+func (b *Board) XWing() bool {
+	return b.deduce(b.fish(2))
+}
+
+// Swordfish eliminates pencilmarks using swordfish, the X-Wing of three rows
+// and three columns. If the candidates of a digit in three rows lie in the same
+// three columns, the digit can't go anywhere else in those columns, and the
+// same with rows and columns swapped. It returns whether any pencilmark was
+// dropped.
+func (b *Board) Swordfish() bool {
+	return b.deduce(b.fish(3))
+}
+
+// fish iterates the deductions of fish of n rows and n columns.
+func (b *Board) fish(n int) iter.Seq[deduction] {
+	return func(yield func(deduction) bool) {
+		for d := uint(1); d <= Size; d++ {
+			rows, _ := b.placements(d)
+			for base, cover := range fishes(rows, n) {
+				for j := range Size {
+					if cover&(1<<j) == 0 {
+						continue
+					}
+					for i := range b.Col(j) {
+						if base&(1<<i) == 0 && !b.drop(i, j, d, yield) {
+							return
+						}
+					}
+				}
+			}
+
+			_, cols := b.placements(d)
+			for base, cover := range fishes(cols, n) {
+				for i := range Size {
+					if cover&(1<<i) == 0 {
+						continue
+					}
+					for j := range b.Row(i) {
+						if base&(1<<j) == 0 && !b.drop(i, j, d, yield) {
+							return
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// fishes iterates the fish of n lines in lines, the [Board.placements] of a
+// digit. It yields the bitmask of the n base lines along with the bitmask of
+// the n cross lines their candidates lie in.
+func fishes(lines [Size]uint, n int) iter.Seq2[uint, uint] {
+	return func(yield func(uint, uint) bool) {
+		candidates := []int{}
+		for x, l := range lines {
+			if cnt := bits.OnesCount(l); 1 < cnt && cnt <= n {
+				candidates = append(candidates, x)
+			}
+		}
+
+		for subset := range combinations(len(candidates), n) {
+			base, cover := uint(0), uint(0)
+			for _, x := range subset {
+				base |= 1 << candidates[x]
+				cover |= lines[candidates[x]]
+			}
+			if bits.OnesCount(cover) == n && !yield(base, cover) {
+				return
+			}
+		}
+	}
+}
+
+// YWing eliminates pencilmarks using Y-Wings. If a pivot cell with the
+// pencilmarks A and B sees a pincer cell with A and C and another with B and C,
+// one of the pincers is C, so C can't go in any cell seeing both pincers. It
+// returns whether any pencilmark was dropped.
+func (b *Board) YWing() bool {
+	return b.deduce(b.yWing())
+}
+
+// yWing iterates the deductions of [Board.YWing].
+func (b *Board) yWing() iter.Seq[deduction] {
+	return func(yield func(deduction) bool) {
+		for pxy, pivot := range b.Unsolved() {
+			if pivot.Count() != 2 {
+				continue
+			}
+
+			coords, wings := [][]int{}, []Cell{}
+			for xy, c := range b.Peers(pxy[0], pxy[1]) {
+				if c.Count() == 2 && c.Intersect(*pivot).Count() == 1 {
+					coords = append(coords, xy)
+					wings = append(wings, *c)
+				}
+			}
+
+			for pair := range combinations(len(wings), 2) {
+				w1, w2 := wings[pair[0]], wings[pair[1]]
+				if w1.Intersect(*pivot) == w2.Intersect(*pivot) || w1.Without(*pivot) != w2.Without(*pivot) {
+					continue
+				}
+				d := w1.Without(*pivot).Digit()
+				xy1, xy2 := coords[pair[0]], coords[pair[1]]
+				for xy := range b.Peers(xy1[0], xy1[1]) {
+					if sees(xy, xy2) && !b.drop(xy[0], xy[1], d, yield) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+// SimpleColoring eliminates pencilmarks using simple coloring. The cells of the
+// units where a digit can go only in two cells are linked, and the chains of
+// linked cells are colored with two alternating colors, so that the digit goes
+// in the cells of exactly one of the colors. If two cells of a color see each
+// other, the digit goes in the cells of the other color. Otherwise the digit
+// can't go in any cell seeing both colors. It returns whether any pencilmark
+// was dropped.
+func (b *Board) SimpleColoring() bool {
+	return b.deduce(b.simpleColoring())
+}
+
+// simpleColoring iterates the deductions of [Board.SimpleColoring].
+func (b *Board) simpleColoring() iter.Seq[deduction] {
+	return func(yield func(deduction) bool) {
+		for d := uint(1); d <= Size; d++ {
+			links := [Size * Size][]int{}
+			for _, unit := range unitIndices {
+				cells := b.unitCells(unit)
+				if CountDigit(cells[:], d) != 2 {
+					continue
+				}
+				pair := []int{}
+				for x, c := range cells {
+					if c.IsSet(d) {
+						pair = append(pair, unit[x])
+					}
+				}
+				links[pair[0]] = append(links[pair[0]], pair[1])
+				links[pair[1]] = append(links[pair[1]], pair[0])
+			}
+
+			// colors are 1 and 2 for the colored cells, 0 for the rest.
+			colors := [Size * Size]int{}
+			for k := range Size * Size {
+				if len(links[k]) == 0 || colors[k] != 0 {
+					continue
+				}
+
+				chain := [2][][]int{}
+				colors[k] = 1
+				for queue := []int{k}; len(queue) > 0; queue = queue[1:] {
+					k := queue[0]
+					chain[colors[k]-1] = append(chain[colors[k]-1], []int{k / Size, k % Size})
+					for _, l := range links[k] {
+						if colors[l] == 0 {
+							colors[l] = 3 - colors[k]
+							queue = append(queue, l)
+						}
+					}
+				}
+
+				found := false
+				for s := range b.colorChain(d, chain) {
+					found = true
+					if !yield(s) {
+						return
+					}
+				}
+				if found {
+					break
+				}
+			}
+		}
+	}
+}
+
+// colorChain iterates the deductions of [Board.SimpleColoring] on the cells of
+// the two colors of a chain of the digit d.
+func (b *Board) colorChain(d uint, chain [2][][]int) iter.Seq[deduction] {
+	return func(yield func(deduction) bool) {
+		for color, cells := range chain {
+			for pair := range combinations(len(cells), 2) {
+				if sees(cells[pair[0]], cells[pair[1]]) {
+					for _, xy := range chain[1-color] {
+						if !b.put(xy[0], xy[1], d, yield) {
+							return
+						}
+					}
+					return
+				}
+			}
+		}
+
+		for xy, c := range b.Unsolved() {
+			if !c.IsSet(d) {
+				continue
+			}
+			seen := [2]bool{}
+			for color, cells := range chain {
+				seen[color] = slices.ContainsFunc(cells, func(cxy []int) bool { return sees(xy, cxy) })
+			}
+			if seen[0] && seen[1] && !b.drop(xy[0], xy[1], d, yield) {
+				return
+			}
+		}
+	}
+}
+
+// WXYZWing eliminates pencilmarks using WXYZ-Wings. A WXYZ-Wing is a pivot cell
+// and three cells seeing it, with four digits between them, such that for all
+// but one of the digits, Z, the cells having the digit see each other. Each of
+// those digits goes in at most one of the four cells, so one of the cells
+// having Z is Z, and Z can't go in any cell seeing all of them. It returns
+// whether any pencilmark was dropped.
+func (b *Board) WXYZWing() bool {
+	return b.deduce(b.wxyzWing())
+}
+
+// wxyzWing iterates the deductions of [Board.WXYZWing].
+func (b *Board) wxyzWing() iter.Seq[deduction] {
+	return func(yield func(deduction) bool) {
+		for pxy, pivot := range b.Unsolved() {
+			if pivot.Count() > 4 {
+				continue
+			}
+
+			coords := [][]int{}
+			for xy, c := range b.Peers(pxy[0], pxy[1]) {
+				if cnt := c.Count(); 1 < cnt && cnt <= 4 && c.Union(*pivot).Count() <= 4 {
+					coords = append(coords, xy)
+				}
+			}
+
+			for wings := range combinations(len(coords), 3) {
+				cells := [][]int{pxy, coords[wings[0]], coords[wings[1]], coords[wings[2]]}
+				for s := range b.wxyzWingOf(cells) {
+					if !yield(s) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+// wxyzWingOf iterates the deductions of [Board.WXYZWing] on the four cells with
+// the coordinates cells.
+func (b *Board) wxyzWingOf(cells [][]int) iter.Seq[deduction] {
+	return func(yield func(deduction) bool) {
+		digits := Cell(0)
+		for _, xy := range cells {
+			digits = digits.Union(*b.At(xy[0], xy[1]))
+		}
+		if digits.Count() != 4 {
+			return
+		}
+
+		z, zs := uint(0), [][]int{}
+		for d := range digits.Digits() {
+			at := [][]int{}
+			for _, xy := range cells {
+				if b.At(xy[0], xy[1]).IsSet(d) {
+					at = append(at, xy)
+				}
+			}
+
+			restricted := true
+			for pair := range combinations(len(at), 2) {
+				restricted = restricted && sees(at[pair[0]], at[pair[1]])
+			}
+			if !restricted {
+				if z != 0 {
+					return
+				}
+				z, zs = d, at
+			}
+		}
+		if z == 0 {
+			return
+		}
+
+		for xy := range b.Peers(zs[0][0], zs[0][1]) {
+			if !slices.ContainsFunc(zs[1:], func(zxy []int) bool { return !sees(xy, zxy) }) && !b.drop(xy[0], xy[1], z, yield) {
+				return
+			}
+		}
+	}
+}
+
+// BUG resolves a cell using the bivalue universal grave. If all cells that
+// are not single digit have two pencilmarks but one with three, and without
+// one of its three digits every digit would be a pencilmark of zero or two
+// cells of each row, column and box, the puzzle would have more than one
+// solution unless the cell is that digit. The cell is set to the digit. It
+// returns whether the cell was set.
+func (b *Board) BUG() bool {
+	return b.deduce(b.bug())
+}
+
+// bug iterates the deductions of [Board.BUG].
+func (b *Board) bug() iter.Seq[deduction] {
+	return func(yield func(deduction) bool) {
+		var odd []int
+		for xy, c := range b.Unsolved() {
+			switch c.Count() {
+			case 2:
+			case 3:
+				if odd != nil {
+					return
+				}
+				odd = xy
+			default:
+				return
+			}
+		}
+		if odd == nil {
+			return
+		}
+
+		for d := range b.At(odd[0], odd[1]).Digits() {
+			if b.bugWithout(odd, d) {
+				yield(deduction{odd[0], odd[1], d, true})
+				return
+			}
+		}
+	}
+}
+
+// bugWithout determines whether without the pencilmark d of the cell with the
+// coordinates odd every digit is a pencilmark of zero or two of the cells that
+// are not single digit in each row, column and box.
+func (b *Board) bugWithout(odd []int, d uint) bool {
+	for unit := range b.units() {
+		counts := [Size + 1]int{}
+		for xy, c := range unit {
+			if c.Single() {
+				continue
+			}
+			for e := range c.Digits() {
+				if e != d || xy[0] != odd[0] || xy[1] != odd[1] {
+					counts[e]++
+				}
+			}
+		}
+		for _, cnt := range counts {
+			if cnt != 0 && cnt != 2 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// sees determines whether the cells with coordinates a and b are different
+// cells sharing a row, column or box.
+func sees(a, b []int) bool {
+	if a[0] == b[0] && a[1] == b[1] {
+		return false
+	}
+	ax, ay := BoxOrigin(a[0], a[1])
+	bx, by := BoxOrigin(b[0], b[1])
+	return a[0] == b[0] || a[1] == b[1] || (ax == bx && ay == by)
+}
+
+// placements returns, for each row, the bitmask of columns where d is a
+// pencilmark and, for each column, the bitmask of rows where d is a
+// pencilmark.
+func (b *Board) placements(d uint) (rows, cols [Size]uint) {
+	for i := range Size {
+		for j, c := range b.Row(i) {
+			if c.IsSet(d) {
+				rows[i] |= 1 << j
+				cols[j] |= 1 << i
+			}
+		}
+	}
+	return rows, cols
+}
+
+// CountDigit is the number of cells that have d as a pencilmark, as in the
+// units of [Board.Units].
+func CountDigit(cells []*Cell, d uint) int {
+	cnt := 0
+	for _, c := range cells {
+		if c.IsSet(d) {
+			cnt++
+		}
+	}
+	return cnt
+}
+
+// same folds v into seen, the common value of the values seen so far. seen is
+// -1 before the first value and becomes -2 once the values differ.
+func same(seen, v int) int {
+	if seen == -1 || seen == v {
+		return v
+	}
+	return -2
+}
+
+// Eliminate drops the pencilmark d from the cell in the ith row jth column. If
+// the cell becomes a single digit it is set. changed is whether the pencilmark
+// was dropped, contradiction is whether a cell was left without pencilmarks.
+// If the coordinates or d are out of range nothing is dropped.
+func (b *Board) Eliminate(i, j int, d uint) (changed, contradiction bool) {
+	c, err := b.AtSafe(i, j)
+	if err != nil || d < 1 || d > Size || !c.IsSet(d) {
+		return false, false
+	}
+	if c.Drop(d).Single() {
+		return true, !b.set(i, j, c.Digit(), nil)
+	}
+	return true, *c == 0
+}
+
+// Units iterates the rows, columns and boxes of the board, in this order, each
+// as the slice of its cells.
+func (b *Board) Units() iter.Seq[[]*Cell] {
+	return func(yield func([]*Cell) bool) {
+		for unit := range b.units() {
+			cells := make([]*Cell, 0, Size)
+			for _, c := range unit {
+				cells = append(cells, c)
+			}
+			if !yield(cells) {
+				return
+			}
+		}
+	}
+}
+
+// units iterates the rows, columns and boxes of the board. Each unit yields the
+// coordinates of its cells along with the cells.
+func (b *Board) units() iter.Seq[iter.Seq2[[]int, *Cell]] {
+	return func(yield func(iter.Seq2[[]int, *Cell]) bool) {
+		for i := range Size {
+			row := func(yield func([]int, *Cell) bool) {
+				for j, c := range b.Row(i) {
+					if !yield([]int{i, j}, c) {
+						return
+					}
+				}
+			}
+			if !yield(row) {
+				return
+			}
+		}
+		for j := range Size {
+			col := func(yield func([]int, *Cell) bool) {
+				for i, c := range b.Col(j) {
+					if !yield([]int{i, j}, c) {
+						return
+					}
+				}
+			}
+			if !yield(col) {
+				return
+			}
+		}
+		for n := range Size {
+			if !yield(b.BoxByIndex(n)) {
+				return
+			}
+		}
+	}
+}
+
+// unitIndices are the indices of the cells of the units of [Board.units], in
+// the same order, for the hot paths of the techniques.
+var unitIndices = func() (u [3 * Size][]int) {
+	n := 0
+	for unit := range EmptyBoard().units() {
+		for xy := range unit {
+			u[n] = append(u[n], xy[0]*Size+xy[1])
+		}
+		n++
+	}
+	return u
+}()
+
+// unitCells is the cells with the indices unit, one of the [unitIndices].
+func (b *Board) unitCells(unit []int) (cells [Size]*Cell) {
+	for x, k := range unit {
+		cells[x] = &b.cells[k]
+	}
+	return cells
+}
+
+// combinations iterates the combinations of k out of the indices 0 to n-1, each
+// in ascending order. The yielded slice is reused between iterations.
+func combinations(n, k int) iter.Seq[[]int] {
+	return func(yield func([]int) bool) {
+		if k > n {
+			return
+		}
+		c := make([]int, k)
+		for x := range c {
+			c[x] = x
+		}
+		for {
+			if !yield(c) {
+				return
+			}
+			x := k - 1
+			for x >= 0 && c[x] == n-k+x {
+				x--
+			}
+			if x < 0 {
+				return
+			}
+			c[x]++
+			for y := x + 1; y < k; y++ {
+				c[y] = c[y-1] + 1
+			}
+		}
+	}
+}
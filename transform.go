@@ -0,0 +1,52 @@
+package main
+
+// Rotate90 returns a copy of the board rotated clockwise by 90 degrees.
+func (b *Board) Rotate90() *Board {
+	return b.transform(func(i, j int) (int, int) { return Size - 1 - j, i })
+}
+
+// ReflectH returns a copy of the board mirrored left to right.
+func (b *Board) ReflectH() *Board {
+	return b.transform(func(i, j int) (int, int) { return i, Size - 1 - j })
+}
+
+// Transpose returns a copy of the board mirrored along the main diagonal,
+// swapping rows and columns.
+func (b *Board) Transpose() *Board {
+	return b.transform(func(i, j int) (int, int) { return j, i })
+}
+
+// transform returns a copy of the board where the cell in the ith row jth
+// column, along with whether it is a given, comes from the cell at src(i, j).
+func (b *Board) transform(src func(i, j int) (int, int)) *Board {
+	t := Board{}
+	for i := range Size {
+		for j := range Size {
+			x, y := src(i, j)
+			t.cells[i*Size+j] = b.cells[x*Size+y]
+			t.given[i*Size+j] = b.given[x*Size+y]
+		}
+	}
+	return &t
+}
+
+// Relabel returns a copy of the board with every digit d replaced by
+// perm[d-1] in all pencilmarks. If perm is not a permutation of the digits 1
+// to [Size] it returns nil.
+func (b *Board) Relabel(perm [Size]uint) *Board {
+	seen := Cell(0)
+	for _, d := range perm {
+		if d < 1 || d > Size || seen.IsSet(d) {
+			return nil
+		}
+		seen.Set(d)
+	}
+
+	t := Board{given: b.given}
+	for k, c := range b.cells {
+		for d := range c.Digits() {
+			t.cells[k].Set(perm[d-1])
+		}
+	}
+	return &t
+}